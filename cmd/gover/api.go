@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/desertwitch/gover/internal/api"
+)
+
+const (
+	// apiLogRecords is the amount of most recent log records held for the API.
+	apiLogRecords = 1000
+
+	// apiUpdateInterval is the interval at which the API pushes updates.
+	apiUpdateInterval = time.Second
+)
+
+// newAPILogBuffer returns a pointer to a new [api.LogBuffer], which is added
+// to the application's logging (so that it collects all following logs).
+func newAPILogBuffer() *api.LogBuffer {
+	logBuffer := api.NewLogBuffer(apiLogRecords, slog.LevelDebug)
+	slogMan.AddHandler("api", logBuffer)
+
+	return logBuffer
+}
+
+// startAPI is a helper function to start serving the application's API on a
+// given address. The returned function stops the serving of the API again.
+func startAPI(ctx context.Context, cancel context.CancelFunc, app *app, address string, logBuffer *api.LogBuffer) (func(), error) {
+	listener, err := api.Listen(address)
+	if err != nil {
+		return nil, fmt.Errorf("(main-api) %w", err)
+	}
+
+	apiHandler := api.NewHandler(ctx, cancel, app.queueManager, app.limiter, logBuffer, apiUpdateInterval)
+
+	serveCtx, serveCancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := apiHandler.Serve(serveCtx, listener); err != nil {
+			slog.Error("API failure.",
+				"err", err,
+			)
+		}
+	}()
+
+	slog.Info("Serving API:",
+		"address", listener.Addr().String(),
+	)
+
+	return func() {
+		serveCancel()
+		<-done
+	}, nil
+}
@@ -6,11 +6,19 @@ import (
 
 	"github.com/desertwitch/gover/internal/allocation"
 	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/eviction"
 	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/hooks"
+	"github.com/desertwitch/gover/internal/intent"
 	"github.com/desertwitch/gover/internal/io"
+	"github.com/desertwitch/gover/internal/journal"
+	"github.com/desertwitch/gover/internal/notify"
 	"github.com/desertwitch/gover/internal/pathing"
+	"github.com/desertwitch/gover/internal/progress"
 	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/report"
 	"github.com/desertwitch/gover/internal/schema"
+	"github.com/desertwitch/gover/internal/throttle"
 	"github.com/desertwitch/gover/internal/ui"
 )
 
@@ -21,6 +29,9 @@ type app struct {
 	// shares is a map of all [schema.Share] that will be checked.
 	shares map[string]schema.Share // map[shareName]schema.Share
 
+	// storages is a map of all known [schema.Storage] (disks and pools).
+	storages map[string]schema.Storage // map[storageName]schema.Storage
+
 	// queueManager is a [queue.Manager] for all application operations.
 	queueManager *queue.Manager
 
@@ -29,26 +40,68 @@ type app struct {
 	pathingHandler *pathing.Handler
 	ioHandler      *io.Handler
 	uiHandler      *ui.Handler
+
+	// progressReporter is a [progress.Reporter] for printing the progress
+	// without a UI. It is nil if no such progress was requested.
+	progressReporter *progress.Reporter
+
+	// reportCollector is a [report.Collector] classifying the errors of the
+	// run, for the [report.Report] that is created once the run has finished.
+	reportCollector *report.Collector
+
+	// evictHandler is an [eviction.Handler] for moving files off pools only
+	// above their watermarks. It is nil if no watermarks were configured.
+	evictHandler *eviction.Handler
+
+	// hookHandler is a [hooks.Handler] for running the hook scripts. It is nil
+	// if no hooks are to be run (e.g. for a plan).
+	hookHandler *hooks.Handler
+
+	// hookedShares are the [hooks.SharePre] events of all [schema.Share] that
+	// were enumerated, for running their [hooks.SharePost] hooks.
+	hookedShares []*hooks.Event
+
+	// intentLog is an [intent.Log] recording the intents of all IO operations
+	// of the run (discarding them if no intent log was requested).
+	intentLog *intent.Log
+
+	// journalWriter is a [journal.Writer] recording all completed IO
+	// operations of the run (discarding them if no journal was requested).
+	journalWriter *journal.Writer
+
+	// limiter is a [throttle.Limiter] limiting the bandwidth of the IO, with
+	// limits that can be changed at runtime.
+	limiter *throttle.Limiter
+
+	// notifyHandler is a [notify.Handler] for sending Unraid notifications. It
+	// is nil if no notifications are to be sent (e.g. for a plan).
+	notifyHandler *notify.Handler
 }
 
 // newApp returns a pointer to a new [app].
 func newApp(shares map[string]schema.Share,
+	storages map[string]schema.Storage,
 	queueManager *queue.Manager,
 	fsHandler *filesystem.Handler,
 	allocHandler *allocation.Handler,
 	pathingHandler *pathing.Handler,
 	ioHandler *io.Handler,
 	uiHandler *ui.Handler,
+	progressReporter *progress.Reporter,
+	reportCollector *report.Collector,
 ) *app {
 	return &app{
-		config:         configuration.NewAppConfiguration(),
-		shares:         shares,
-		queueManager:   queueManager,
-		fsHandler:      fsHandler,
-		allocHandler:   allocHandler,
-		pathingHandler: pathingHandler,
-		ioHandler:      ioHandler,
-		uiHandler:      uiHandler,
+		config:           configuration.NewAppConfiguration(),
+		shares:           shares,
+		storages:         storages,
+		queueManager:     queueManager,
+		fsHandler:        fsHandler,
+		allocHandler:     allocHandler,
+		pathingHandler:   pathingHandler,
+		ioHandler:        ioHandler,
+		uiHandler:        uiHandler,
+		progressReporter: progressReporter,
+		reportCollector:  reportCollector,
 	}
 }
 
@@ -61,6 +114,8 @@ func (app *app) Launch(ctx context.Context) error {
 		return fmt.Errorf("(app) %w", err)
 	}
 
+	app.selectEvictions()
+
 	if err := app.Evaluate(ctx); err != nil {
 		return fmt.Errorf("(app) %w", err)
 	}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/desertwitch/gover/internal/pathing"
+	"github.com/desertwitch/gover/internal/plan"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/desertwitch/gover/internal/validation"
+)
+
+// LaunchApply starts the application for applying a previously persisted
+// [plan.Plan] (as read from the given path):
+//   - Verification of all planned [schema.Moveable] against any drift.
+//   - IO to move all [schema.Moveable] to their final destinations.
+func (app *app) LaunchApply(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("(app) failed to open plan: %w", err)
+	}
+	defer f.Close()
+
+	p, err := plan.ReadJSON(f)
+	if err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	slog.Info("Applying plan:",
+		"path", path,
+		"createdAt", p.CreatedAt,
+		"items", p.TotalItems(),
+	)
+
+	if err := app.Apply(ctx, p); err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	if err := app.IO(ctx); err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	return nil
+}
+
+// Apply is the principal method for verifying all [schema.Moveable] of a
+// previously persisted [plan.Plan] and enqueueing them into the
+// [queue.IOManager]. It takes the place of both enumeration and evaluation, so
+// that exactly the planned moves happen (minus those that have drifted). This
+// process happens concurrently, meaning that multiple [schema.Share]'s are
+// verified at the same time.
+func (app *app) Apply(ctx context.Context, p *plan.Plan) error {
+	tasker := queue.NewTaskManager()
+
+	app.queueManager.EvaluationManager.Enqueue(p.Moveables(app.shares, app.storages)...)
+
+	for share, shareQueue := range app.queueManager.EvaluationManager.GetQueues() {
+		tasker.Add(
+			func(share schema.Share, shareQueue *queue.EvaluationShareQueue) func() {
+				return func() {
+					if err := app.verifyToIO(ctx, shareQueue); err != nil {
+						slog.Warn("Skipped verifying share due to failure:",
+							"err", err,
+							"share", share.GetName(),
+						)
+						app.reportCollector.AddFailure(progress.StageEvaluation, share.GetName(), err)
+					}
+				}
+			}(share, shareQueue),
+		)
+	}
+
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.Evaluation()); err != nil {
+		return fmt.Errorf("(app-apply) %w", err)
+	}
+
+	return nil
+}
+
+// verifyToIO is the processing logic for an [queue.EvaluationShareQueue] that
+// was filled from a [plan.Plan]. Every [schema.Moveable] is re-checked against
+// its recorded metadata and destination, enqueueing those still matching the
+// plan into the [queue.IOManager].
+func (app *app) verifyToIO(ctx context.Context, q *queue.EvaluationShareQueue) error {
+	if err := q.DequeueAndProcessConc(ctx, app.config.Concurrency.Share(), func(m *schema.Moveable) (int, error) {
+		if err := app.verifyPlanned(m); err != nil {
+			slog.Warn("Skipped job: no longer matching the plan",
+				"err", err,
+				"job", m.SourcePath,
+				"share", m.Share.GetName(),
+			)
+
+			return queue.DecisionSkipped, err
+		}
+
+		for _, subelem := range slices.Concat(m.Hardlinks, m.Symlinks) {
+			if err := app.verifyPlanned(subelem); err != nil {
+				slog.Warn("Skipped job: subjob no longer matching the plan",
+					"err", err,
+					"subjob", subelem.SourcePath,
+					"job", m.SourcePath,
+					"share", m.Share.GetName(),
+				)
+
+				return queue.DecisionSkipped, err
+			}
+		}
+
+		if err := validation.ValidateMoveable(m); err != nil {
+			return queue.DecisionSkipped, err
+		}
+
+		return queue.DecisionSuccess, nil
+	}); err != nil {
+		return fmt.Errorf("(app-verify) %w", err)
+	}
+
+	app.queueManager.IOManager.Enqueue(q.GetSuccessful()...)
+
+	return nil
+}
+
+// verifyPlanned checks a planned [schema.Moveable] for any drift of its source
+// since the planning and re-checks that its destination does not yet exist.
+func (app *app) verifyPlanned(m *schema.Moveable) error {
+	current, err := app.fsHandler.GetMetadata(m.SourcePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("(app-verify) %w", plan.ErrSourceVanished)
+		}
+
+		return fmt.Errorf("(app-verify) %w", err)
+	}
+
+	if err := plan.CheckDrift(m.Metadata, current); err != nil {
+		return fmt.Errorf("(app-verify) %w", err)
+	}
+
+	existsPath, err := app.pathingHandler.ExistsOnStorage(m)
+	if err != nil {
+		return fmt.Errorf("(app-verify) %w", err)
+	}
+
+	// A directory is allowed to exist, that gets handled later in IO.
+	if !m.Metadata.IsDir && existsPath != "" {
+		return fmt.Errorf("(app-verify) %w: %s", pathing.ErrPathExistsOnDest, existsPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/eviction"
+	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/processors"
+	"github.com/desertwitch/gover/internal/throttle"
+)
+
+const (
+	// defaultConfigPath is the path of the application's configuration file,
+	// which is only read if it exists (unless requested explicitly).
+	defaultConfigPath = "/boot/config/plugins/gover/gover.cfg"
+
+	// configFlagName is the name of the flag holding the configuration file's
+	// path, which cannot be set from the configuration file itself.
+	configFlagName = "config"
+)
+
+// limitsValue is a [flag.Value] holding worker limits, given as comma-separated
+// "name=workers" pairs.
+type limitsValue struct {
+	limits   map[string]int
+	validate func(map[string]int) error
+}
+
+// limitsFlag defines a [limitsValue] flag with the given name and usage. The
+// optional validate function is called on the limits whenever they are set.
+func limitsFlag(name string, usage string, validate func(map[string]int) error) *limitsValue {
+	v := &limitsValue{
+		limits:   make(map[string]int),
+		validate: validate,
+	}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the worker limits as comma-separated "name=workers" pairs.
+func (v *limitsValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(v.limits))
+	for name, workers := range v.limits {
+		pairs = append(pairs, name+"="+strconv.Itoa(workers))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Set parses and validates the worker limits from comma-separated
+// "name=workers" pairs.
+func (v *limitsValue) Set(s string) error {
+	limits, err := configuration.ParseLimits(s)
+	if err != nil {
+		return err
+	}
+
+	if v.validate != nil {
+		if err := v.validate(limits); err != nil {
+			return err
+		}
+	}
+
+	v.limits = limits
+
+	return nil
+}
+
+// choiceValue is a [flag.Value] holding one of a set of allowed choices.
+type choiceValue struct {
+	value   string
+	choices []string
+}
+
+// choiceFlag defines a [choiceValue] flag with the given name, default value,
+// allowed choices and usage.
+func choiceFlag(name string, value string, choices []string, usage string) *choiceValue {
+	v := &choiceValue{
+		value:   value,
+		choices: choices,
+	}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the chosen value.
+func (v *choiceValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.value
+}
+
+// Set sets the chosen value, if it is one of the allowed choices.
+func (v *choiceValue) Set(s string) error {
+	if !slices.Contains(v.choices, s) {
+		return fmt.Errorf("%w: %q is not one of %q", ErrInvalidArgs, s, v.choices)
+	}
+
+	v.value = s
+
+	return nil
+}
+
+// rulesValue is a [flag.Value] holding declarative pipeline rules, see
+// [processors.Rule] for their textual representation.
+type rulesValue struct {
+	text  string
+	rules []*processors.Rule
+}
+
+// rulesFlag defines a [rulesValue] flag with the given name and usage.
+func rulesFlag(name string, usage string) *rulesValue {
+	v := &rulesValue{}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the textual representation of the rules.
+func (v *rulesValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses and validates the rules from their textual representation.
+func (v *rulesValue) Set(s string) error {
+	rules, err := processors.ParseRules(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.rules = rules
+
+	return nil
+}
+
+// patternsValue is a [flag.Value] holding path patterns, given as
+// "[share:]pattern" entries, see [filesystem.PathPattern] for their syntax.
+type patternsValue struct {
+	text     string
+	patterns map[string][]string
+}
+
+// patternsFlag defines a [patternsValue] flag with the given name and usage.
+func patternsFlag(name string, usage string) *patternsValue {
+	v := &patternsValue{
+		patterns: make(map[string][]string),
+	}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the textual representation of the path patterns.
+func (v *patternsValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses and validates the path patterns from their textual representation.
+func (v *patternsValue) Set(s string) error {
+	patterns, err := filesystem.ParsePathPatterns(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.patterns = patterns
+
+	return nil
+}
+
+// forShare returns the path patterns applying to a [schema.Share] (including
+// those applying to all shares).
+func (v *patternsValue) forShare(name string) []string {
+	return slices.Concat(v.patterns[filesystem.AllShares], v.patterns[name])
+}
+
+// orderValue is a [flag.Value] holding comma-separated ordering policies, see
+// [processors.OrderPolicies] for the known policies.
+type orderValue struct {
+	text     string
+	policies []string
+}
+
+// orderFlag defines an [orderValue] flag with the given name and usage.
+func orderFlag(name string, usage string) *orderValue {
+	v := &orderValue{}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the comma-separated ordering policies.
+func (v *orderValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses and validates the comma-separated ordering policies.
+func (v *orderValue) Set(s string) error {
+	policies, err := processors.ParseOrderPolicies(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.policies = policies
+
+	return nil
+}
+
+// watermarksValue is a [flag.Value] holding the watermarks of pools, given as
+// comma-separated "pool=high:low" pairs.
+type watermarksValue struct {
+	text       string
+	watermarks map[string]eviction.Watermarks
+}
+
+// watermarksFlag defines a [watermarksValue] flag with the given name and
+// usage.
+func watermarksFlag(name string, usage string) *watermarksValue {
+	v := &watermarksValue{
+		watermarks: make(map[string]eviction.Watermarks),
+	}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the watermarks as comma-separated "pool=high:low" pairs.
+func (v *watermarksValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses and validates the watermarks from comma-separated
+// "pool=high:low" pairs.
+func (v *watermarksValue) Set(s string) error {
+	watermarks, err := eviction.ParseWatermarks(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.watermarks = watermarks
+
+	return nil
+}
+
+// rateValue is a [flag.Value] holding a bandwidth limit, given as a size per
+// second (e.g. "50MiB").
+type rateValue struct {
+	text string
+	rate uint64
+}
+
+// rateFlag defines a [rateValue] flag with the given name and usage.
+func rateFlag(name string, usage string) *rateValue {
+	v := &rateValue{}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the bandwidth limit as a size per second.
+func (v *rateValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses the bandwidth limit from a size per second.
+func (v *rateValue) Set(s string) error {
+	rate, err := throttle.ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.rate = rate
+
+	return nil
+}
+
+// ratesValue is a [flag.Value] holding bandwidth limits, given as
+// comma-separated "name=rate" pairs.
+type ratesValue struct {
+	text  string
+	rates map[string]uint64
+}
+
+// ratesFlag defines a [ratesValue] flag with the given name and usage.
+func ratesFlag(name string, usage string) *ratesValue {
+	v := &ratesValue{
+		rates: make(map[string]uint64),
+	}
+	flag.Var(v, name, usage)
+
+	return v
+}
+
+// String returns the bandwidth limits as comma-separated "name=rate" pairs.
+func (v *ratesValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses the bandwidth limits from comma-separated "name=rate" pairs.
+func (v *ratesValue) Set(s string) error {
+	rates, err := throttle.ParseRates(s)
+	if err != nil {
+		return err
+	}
+
+	v.text = s
+	v.rates = rates
+
+	return nil
+}
+
+// configFilePath returns the path of the configuration file that is to be
+// read, preferring the flag and then the environment over the default path.
+// An empty path is returned if there is no configuration file to be read.
+func configFilePath() string {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == configFlagName {
+			explicit = true
+		}
+	})
+
+	if explicit {
+		return *configPath
+	}
+
+	if path, ok := os.LookupEnv(configuration.EnvPrefix + configuration.FlagNameToKey(configFlagName)); ok {
+		return path
+	}
+
+	if _, err := os.Stat(defaultConfigPath); errors.Is(err, fs.ErrNotExist) {
+		return ""
+	}
+
+	return defaultConfigPath
+}
+
+// loadConfig layers the application's options from the defaults, the
+// configuration file, the environment and the command-line flags (in that order
+// of precedence), see [configuration.Handler.ApplyLayers].
+func loadConfig() error {
+	configHandler := configuration.NewHandler(&configuration.GodotenvProvider{})
+
+	path := configFilePath()
+
+	if err := configHandler.ApplyLayers(flag.CommandLine, path, os.Environ(), configFlagName); err != nil {
+		return fmt.Errorf("(main-config) %w", err)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel.String())); err != nil {
+		return fmt.Errorf("(main-config) %w", err)
+	}
+	termLevel.Set(level)
+
+	if path != "" {
+		slog.Debug("Configuration file read:",
+			"path", path,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// drainShare is an adapter for a [schema.Share] that is drained from a
+// [schema.Storage], which is no longer an allocation target for it. Its
+// contents are always allocated by the most-free method, so that the writes
+// are spread across as many target [schema.Disk] as possible.
+type drainShare struct {
+	schema.Share
+
+	// drained is the name of the [schema.Storage] being drained.
+	drained string
+}
+
+// GetAllocator returns the most-free allocation method.
+func (s *drainShare) GetAllocator() string {
+	return configuration.AllocMostFree
+}
+
+// GetIncludedDisks returns the included [schema.Disk] of the [schema.Share],
+// except for the [schema.Storage] being drained.
+func (s *drainShare) GetIncludedDisks() map[string]schema.Disk {
+	disks := s.Share.GetIncludedDisks()
+	delete(disks, s.drained)
+
+	return disks
+}
+
+// LaunchDrain starts the application for draining a [schema.Storage] (a disk
+// or a pool), moving the contents of all [schema.Share] on it to their other
+// included disks, regardless of the [schema.Share] caching settings. Anything
+// that could not be placed (or moved) is logged once the draining has finished.
+func (app *app) LaunchDrain(ctx context.Context, name string) error {
+	storage, exists := app.storages[name]
+	if !exists {
+		return fmt.Errorf("(app-drain) %w: %s", ErrUnknownStorage, name)
+	}
+
+	if err := app.EnumerateDrain(ctx, storage); err != nil {
+		return fmt.Errorf("(app-drain) %w", err)
+	}
+
+	if err := app.Evaluate(ctx); err != nil {
+		return fmt.Errorf("(app-drain) %w", err)
+	}
+
+	if err := app.IO(ctx); err != nil {
+		return fmt.Errorf("(app-drain) %w", err)
+	}
+
+	app.logUnplaced(storage)
+
+	return nil
+}
+
+// EnumerateDrain is the principal method for querying all [schema.Share] on a
+// [schema.Storage] being drained for candidate [schema.Moveable] and enqueueing
+// them into a [queue.EvaluationManager], for allocation to the other included
+// disks of their [schema.Share].
+func (app *app) EnumerateDrain(ctx context.Context, storage schema.Storage) error {
+	tasker := queue.NewTaskManager()
+
+	for _, share := range app.shares {
+		if !app.runSharePreHook(ctx, share, storage, nil) {
+			continue
+		}
+
+		app.queueManager.EnumerationManager.Enqueue(&queue.EnumerationTask{
+			Share:  share,
+			Source: storage,
+			Function: func(share schema.Share, src schema.Storage) func() (int, error) {
+				return func() (int, error) {
+					return app.enumerateToEvaluation(ctx, share, src, nil)
+				}
+			}(&drainShare{Share: share, drained: storage.GetName()}, storage),
+		})
+	}
+
+	for source, sourceQueue := range app.queueManager.EnumerationManager.GetQueues() {
+		tasker.Add(func(source schema.Storage, sourceQueue *queue.EnumerationSourceQueue) func() {
+			return func() {
+				if err := app.processEnumerationQueue(ctx, source, sourceQueue); err != nil {
+					app.reportCollector.AddFailure(progress.StageEnumeration, source.GetName(), err)
+				}
+			}
+		}(source, sourceQueue))
+	}
+
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.Enumeration()); err != nil {
+		return fmt.Errorf("(app-drain-enum) %w", err)
+	}
+
+	return nil
+}
+
+// logUnplaced logs all [schema.Moveable] that could not be placed (skipped in
+// the evaluation) or moved (skipped in the IO) while draining a
+// [schema.Storage], and therefore remain on it.
+func (app *app) logUnplaced(storage schema.Storage) {
+	unplaced := 0
+
+	for _, q := range app.queueManager.EvaluationManager.GetQueues() {
+		for _, m := range q.GetSkipped() {
+			slog.Warn("Remains on drained storage: could not be placed",
+				"job", m.SourcePath,
+				"share", m.Share.GetName(),
+			)
+			unplaced++
+		}
+	}
+
+	for _, q := range app.queueManager.IOManager.GetQueues() {
+		for _, m := range q.GetSkipped() {
+			slog.Warn("Remains on drained storage: could not be moved",
+				"job", m.SourcePath,
+				"share", m.Share.GetName(),
+			)
+			unplaced++
+		}
+	}
+
+	slog.Info("Draining storage done:",
+		"storage", storage.GetName(),
+		"unplaced", unplaced,
+	)
+}
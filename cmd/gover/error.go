@@ -10,4 +10,45 @@ var (
 	// ErrPipePostProcFailed occurs when a post-processing pipeline has failed
 	// during an operation.
 	ErrPipePostProcFailed = errors.New("post-processing pipeline has failed")
+
+	// ErrUnknownCommand occurs when an unknown command was given to the
+	// application.
+	ErrUnknownCommand = errors.New("unknown command")
+
+	// ErrTooManyArgs occurs when more command-line arguments were given to the
+	// application than the requested command accepts.
+	ErrTooManyArgs = errors.New("too many arguments")
+
+	// ErrInvalidArgs occurs when the command-line arguments given to the
+	// application do not match what the requested command expects.
+	ErrInvalidArgs = errors.New("invalid arguments")
+
+	// ErrUnknownStorage occurs when an unknown storage name was given to the
+	// application.
+	ErrUnknownStorage = errors.New("unknown storage")
+
+	// ErrUnresolvedLeftover occurs when a leftover temporary file has neither a
+	// (matching) source nor a destination, so that it may be the only copy of a
+	// file.
+	ErrUnresolvedLeftover = errors.New("neither matching source nor destination exists")
+
+	// ErrUnconfirmedLeftover occurs when a leftover temporary file exists next
+	// to its destination, but its removal was not confirmed (as it may also be
+	// a file of the user that merely shares the naming of temporary files).
+	ErrUnconfirmedLeftover = errors.New("removal next to destination not confirmed")
+
+	// ErrNoJournal occurs when a run is to be undone without a journal given to
+	// the application.
+	ErrNoJournal = errors.New("no journal given")
+
+	// ErrLinkTargetMissing occurs when a hardlink of a run is to be undone, but
+	// the file it links to is not undone with it.
+	ErrLinkTargetMissing = errors.New("hardlink target not part of the undo")
+
+	// ErrAlreadyRunning occurs when another instance of the application is
+	// already holding the single-instance lock.
+	ErrAlreadyRunning = errors.New("another instance is already running")
+
+	// ErrMoverRunning occurs when the stock Unraid mover is already running.
+	ErrMoverRunning = errors.New("unraid mover is already running")
 )
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/eviction"
+	"github.com/desertwitch/gover/internal/processors"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// setupEviction is a helper function to establish the [eviction.Handler] of
+// the [app], as requested by the (layered) options. Its pre-processor is added
+// to the evaluation pipelines of all shares and its processor to the IO
+// pipelines of all targets. Without any watermarks, this function is a no-op.
+func (app *app) setupEviction(shareNames []string, storageNames []string) {
+	if len(poolWatermarks.watermarks) == 0 {
+		return
+	}
+
+	for name := range poolWatermarks.watermarks {
+		if name != eviction.AllPools && app.storages[name] == nil {
+			slog.Warn("Skipped watermarks: no matching pool",
+				"pool", name,
+			)
+		}
+	}
+
+	app.evictHandler = eviction.NewHandler(app.fsHandler, poolWatermarks.watermarks, evictBy.String())
+
+	for _, name := range shareNames {
+		pipeline, exists := app.config.Pipelines.EvaluationPipelines[name]
+		if !exists {
+			pipeline = &processors.GenericPipeline[*schema.Moveable]{}
+		}
+		app.config.Pipelines.EvaluationPipelines[name] = pipeline.AddPreProcess(app.evictHandler.PreProcessor())
+	}
+
+	for _, name := range storageNames {
+		pipeline, exists := app.config.Pipelines.IOPipelines[name]
+		if !exists {
+			pipeline = &processors.GenericPipeline[*schema.Moveable]{}
+		}
+		app.config.Pipelines.IOPipelines[name] = pipeline.Add(app.evictHandler.Processor())
+	}
+}
+
+// selectEvictions selects the [schema.Moveable] to be moved off pools with
+// watermarks, out of all candidates of the [queue.EvaluationManager]. Without
+// an [eviction.Handler], this function is a no-op. Any pools that could not be
+// evaluated are recorded as errors of the evaluation.
+func (app *app) selectEvictions() {
+	if app.evictHandler == nil {
+		return
+	}
+
+	var candidates []*schema.Moveable
+	for _, q := range app.queueManager.EvaluationManager.GetQueues() {
+		candidates = append(candidates, q.GetItems()...)
+	}
+
+	if err := app.evictHandler.Select(candidates); err != nil {
+		app.reportCollector.AddError(progress.StageEvaluation, "", "", err)
+	}
+}
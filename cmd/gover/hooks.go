@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/hooks"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/report"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// arrayName is the name passed to hooks for the array as a whole (as the
+// source or target of a [schema.Share]).
+const arrayName = "array"
+
+// setupHooks is a helper function to establish the [hooks.Handler] of the
+// [app], as requested by the (layered) options. Without it (e.g. for a plan),
+// no hooks are run at all.
+func (app *app) setupHooks() {
+	app.hookHandler = hooks.NewHandler(map[string]string{
+		hooks.RunPre:     *hookRunPre,
+		hooks.RunPost:    *hookRunPost,
+		hooks.SharePre:   *hookSharePre,
+		hooks.SharePost:  *hookSharePost,
+		hooks.TargetPost: *hookTargetPost,
+	}, *hookTimeout, schedSettings())
+}
+
+// runHook runs the hook of an [hooks.Event] (if any is set). Without a
+// [hooks.Handler], this function is a no-op.
+func (app *app) runHook(ctx context.Context, e *hooks.Event) error {
+	if app.hookHandler == nil {
+		return nil
+	}
+
+	if err := app.hookHandler.Run(ctx, e); err != nil {
+		return fmt.Errorf("(app-hooks) %w", err)
+	}
+
+	return nil
+}
+
+// runSharePreHook runs the pre hook of a [schema.Share] that is about to be
+// enumerated from the source to the target [schema.Storage] (nil being the
+// array), returning whether the [schema.Share] is to be enumerated. A failed
+// hook skips the [schema.Share], otherwise it is remembered for its post hook.
+func (app *app) runSharePreHook(ctx context.Context, share schema.Share, src schema.Storage, dst schema.Storage) bool {
+	e := &hooks.Event{
+		Hook:   hooks.SharePre,
+		Share:  share.GetName(),
+		Source: storageName(src),
+		Target: storageName(dst),
+	}
+
+	if err := app.runHook(ctx, e); err != nil {
+		slog.Warn("Skipped share: pre hook has failed",
+			"err", err,
+			"share", share.GetName(),
+		)
+		app.reportCollector.AddError(progress.StageEnumeration, share.GetName(), "", err)
+
+		return false
+	}
+
+	if app.hookHandler != nil {
+		app.hookedShares = append(app.hookedShares, e)
+	}
+
+	return true
+}
+
+// runSharePostHooks runs the post hooks of all [schema.Share] that were
+// enumerated, with the counts and bytes of their [schema.Moveable] in the IO.
+func (app *app) runSharePostHooks(ctx context.Context) {
+	for _, pre := range app.hookedShares {
+		e := &hooks.Event{
+			Hook:   hooks.SharePost,
+			Share:  pre.Share,
+			Source: pre.Source,
+			Target: pre.Target,
+		}
+
+		for _, q := range app.queueManager.IOManager.GetQueues() {
+			for _, m := range q.GetSuccessful() {
+				if m.Share.GetName() == e.Share {
+					e.Success++
+					e.Bytes += moveableBytes(m)
+				}
+			}
+			for _, m := range q.GetSkipped() {
+				if m.Share.GetName() == e.Share {
+					e.Skipped++
+				}
+			}
+		}
+		e.Items = e.Success + e.Skipped
+
+		if err := app.runHook(ctx, e); err != nil {
+			slog.Warn("Post hook of share has failed",
+				"err", err,
+				"share", e.Share,
+			)
+			app.reportCollector.AddError(progress.StageIO, e.Share, "", err)
+		}
+	}
+}
+
+// runTargetPostHook runs the post hook of a target [schema.Storage], once its
+// queue has finished, with the counts and bytes of its [schema.Moveable].
+func (app *app) runTargetPostHook(ctx context.Context, target schema.Storage, successful []*schema.Moveable, skipped []*schema.Moveable, bytes uint64) {
+	e := &hooks.Event{
+		Hook:    hooks.TargetPost,
+		Target:  target.GetName(),
+		Items:   len(successful) + len(skipped),
+		Success: len(successful),
+		Skipped: len(skipped),
+		Bytes:   bytes,
+	}
+
+	if err := app.runHook(ctx, e); err != nil {
+		slog.Warn("Post hook of target has failed",
+			"err", err,
+			"target", target.GetName(),
+		)
+		app.reportCollector.AddError(progress.StageIO, "", target.GetName(), err)
+	}
+}
+
+// runRunPostHook runs the post hook of the run, with the [report.Outcome] and
+// the counts and bytes of its IO stage.
+func (app *app) runRunPostHook(ctx context.Context, r *report.Report) {
+	e := &hooks.Event{
+		Hook:    hooks.RunPost,
+		Command: r.Command,
+		Outcome: string(r.Outcome),
+	}
+
+	if ioStage := r.Stage(progress.StageIO); ioStage != nil {
+		e.Items = ioStage.Items
+		e.Success = ioStage.Success
+		e.Skipped = ioStage.Skipped
+		e.Bytes = ioStage.Bytes
+	}
+
+	if err := app.runHook(ctx, e); err != nil {
+		slog.Warn("Post hook of run has failed",
+			"err", err,
+		)
+	}
+}
+
+// storageName returns the name of a [schema.Storage], or [arrayName] for nil.
+func storageName(s schema.Storage) string {
+	if s == nil {
+		return arrayName
+	}
+
+	return s.GetName()
+}
+
+// moveableBytes returns the bytes of a [schema.Moveable] (zero for directories
+// or without any metadata).
+func moveableBytes(m *schema.Moveable) uint64 {
+	if m.Metadata == nil || m.Metadata.IsDir {
+		return 0
+	}
+
+	return m.Metadata.Size
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// lockFilePath is the path of the PID/lock file of the application.
+	lockFilePath = "/var/run/gover.pid"
+
+	// moverPIDFilePath is the path of the PID file of the stock Unraid mover.
+	moverPIDFilePath = "/var/run/mover.pid"
+
+	// lockFilePerms are the permissions of the PID/lock file.
+	lockFilePerms = 0o644
+
+	// lockPollInterval is the interval at which a busy lock is re-checked when
+	// waiting for it.
+	lockPollInterval = time.Second
+)
+
+// instanceLock is a single-instance lock with flock semantics, which also
+// guards against the stock Unraid mover running at the same time. The lock
+// file contains the PID of the holding process.
+type instanceLock struct {
+	sync.Mutex
+
+	path      string
+	moverPath string
+	file      *os.File
+}
+
+// newInstanceLock returns a pointer to a new (not yet acquired)
+// [instanceLock].
+func newInstanceLock(path string, moverPath string) *instanceLock {
+	return &instanceLock{
+		path:      path,
+		moverPath: moverPath,
+	}
+}
+
+// Acquire acquires the [instanceLock]. If either another instance holds the
+// lock or the stock Unraid mover is running, an error is returned, unless wait
+// is set, in which case it blocks until the lock frees (or the context is
+// cancelled).
+func (l *instanceLock) Acquire(ctx context.Context, wait bool) error {
+	waitLogged := false
+
+	for {
+		err := l.tryAcquire()
+		if err == nil {
+			return nil
+		}
+
+		if !wait || (!errors.Is(err, ErrAlreadyRunning) && !errors.Is(err, ErrMoverRunning)) {
+			return err
+		}
+
+		if !waitLogged {
+			slog.Info("Waiting for the lock to free:",
+				"reason", err,
+			)
+			waitLogged = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("(lock) %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Release releases the [instanceLock], if it is held. It is safe to call
+// multiple times and from multiple goroutines.
+func (l *instanceLock) Release() {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.file == nil {
+		return
+	}
+
+	// The lock file is not removed, as another instance may already be
+	// waiting on it. An empty lock file is not held by any process.
+	_ = l.file.Truncate(0)
+	_ = unix.Flock(int(l.file.Fd()), unix.LOCK_UN)
+	_ = l.file.Close()
+
+	l.file = nil
+}
+
+// tryAcquire makes a single non-blocking attempt at acquiring the
+// [instanceLock].
+func (l *instanceLock) tryAcquire() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.file != nil {
+		return nil
+	}
+
+	if pid, alive := pidFileAlive(l.moverPath); alive {
+		return fmt.Errorf("(lock) %w: pid %d", ErrMoverRunning, pid)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, lockFilePerms)
+	if err != nil {
+		return fmt.Errorf("(lock) failed to open: %w", err)
+	}
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		f.Close()
+
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			pid, _ := readPIDFile(l.path)
+
+			return fmt.Errorf("(lock) %w: pid %d", ErrAlreadyRunning, pid)
+		}
+
+		return fmt.Errorf("(lock) failed to flock: %w", err)
+	}
+
+	// Check again, in case the mover started in the meantime.
+	if pid, alive := pidFileAlive(l.moverPath); alive {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+
+		return fmt.Errorf("(lock) %w: pid %d", ErrMoverRunning, pid)
+	}
+
+	if err := f.Truncate(0); err != nil {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+
+		return fmt.Errorf("(lock) failed to truncate: %w", err)
+	}
+
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+
+		return fmt.Errorf("(lock) failed to write pid: %w", err)
+	}
+
+	l.file = f
+
+	return nil
+}
+
+// readPIDFile reads the PID contained in a PID file.
+func readPIDFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("(lock-pid) failed to read: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("(lock-pid) failed to parse: %w", err)
+	}
+
+	return pid, nil
+}
+
+// pidFileAlive returns the PID contained in a PID file and whether the
+// process of that PID is alive. A missing or invalid PID file is not alive.
+func pidFileAlive(path string) (int, bool) {
+	pid, err := readPIDFile(path)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+
+	if err := unix.Kill(pid, 0); err != nil && !errors.Is(err, unix.EPERM) {
+		return pid, false
+	}
+
+	return pid, true
+}
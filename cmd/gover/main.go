@@ -1,6 +1,13 @@
 /*
 gover is a mover-type application for moving files between various storage, and
 according to a defined set of rules, configuration and logical pathways.
+
+Usage:
+
+	gover [flags] [command]
+
+The commands are move (the default), plan, apply, drain, sweep and undo. See
+"gover -help" for the flags and the README for a description of the commands.
 */
 package main
 
@@ -8,27 +15,69 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/desertwitch/gover/internal/allocation"
+	"github.com/desertwitch/gover/internal/api"
 	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/eviction"
 	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/hooks"
+	"github.com/desertwitch/gover/internal/intent"
 	"github.com/desertwitch/gover/internal/io"
+	"github.com/desertwitch/gover/internal/journal"
+	"github.com/desertwitch/gover/internal/notify"
 	"github.com/desertwitch/gover/internal/pathing"
+	"github.com/desertwitch/gover/internal/priority"
+	"github.com/desertwitch/gover/internal/processors"
+	"github.com/desertwitch/gover/internal/progress"
 	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/report"
 	"github.com/desertwitch/gover/internal/schema"
+	"github.com/desertwitch/gover/internal/throttle"
 	"github.com/desertwitch/gover/internal/ui"
 	"github.com/desertwitch/gover/internal/unraid"
 	"github.com/lmittmann/tint"
+	"golang.org/x/sys/unix"
 )
 
 const (
+	// cmdMove is the command for a regular run of the application.
+	cmdMove = "move"
+
+	// cmdPlan is the command for a dry-run of the application, printing the
+	// move plan instead of doing any IO.
+	cmdPlan = "plan"
+
+	// cmdApply is the command for applying a previously persisted move plan.
+	cmdApply = "apply"
+
+	// cmdDrain is the command for moving all files off a disk or pool.
+	cmdDrain = "drain"
+
+	// cmdSweep is the command for removing leftover temporary files.
+	cmdSweep = "sweep"
+
+	// cmdUndo is the command for undoing a previous run from the journal.
+	cmdUndo = "undo"
+
+	// defaultHookTimeout is the default time a hook script may run.
+	defaultHookTimeout = 10 * time.Minute
+
+	// defaultIOLevel is the default IO priority level within the best-effort
+	// class (as is the kernel's default).
+	defaultIOLevel = 4
+
 	// stackTraceBufMax is the limiting size for a requested stack trace.
 	stackTraceBufMax = 1 << 24
 )
@@ -37,23 +86,67 @@ var (
 	// Version is the application's version (filled in during compilation).
 	Version string
 
-	exitCode = 0
-	slogMan  = newSlogManager()
+	exitCode   = exitSuccess
+	slogMan    = newSlogManager()
+	termOutput = os.Stdout
 
 	uiEnabled  = flag.Bool("ui", true, "enable the UI")
+	waitLock   = flag.Bool("wait", false, "wait for another instance (or the Unraid mover) to finish instead of refusing to start")
+	dryRun     = flag.Bool("dry-run", false, "only print the move plan without any IO (same as plan command), or only report leftovers (with sweep command)")
+	sweepNext  = flag.Bool("sweep-confirm", false, "also remove leftovers next to their existing destinations (with sweep command, once reviewed with -dry-run)")
+	jsonOutput = flag.Bool("json", false, "print the move plan as JSON instead of a table")
+	planOut    = flag.String("out", "", "persist the move plan as JSON to this file (for apply command)")
 	cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
 	memprofile = flag.String("memprofile", "", "write memory profile to this file")
+
+	apiAddress       = flag.String("api", "", "serve the HTTP API on a Unix socket (unix:/path) or a loopback address (localhost:port)")
+	metricsAddress   = flag.String("metrics", "", "serve Prometheus metrics on a Unix socket (unix:/path) or a loopback address (localhost:port)")
+	metricsTextfile  = flag.String("metrics-textfile", "", "write Prometheus metrics to this file (for a textfile collector)")
+	progressMode     = choiceFlag("progress", "", []string{"", progress.FormatJSON, progress.FormatText}, "print progress as \"json\" (NDJSON) or \"text\" lines instead of the UI")
+	progressInterval = flag.Duration("progress-interval", time.Second, "interval at which progress is printed")
+	workerLimits     = limitsFlag("workers", "worker limits per stage (e.g. \"enumeration=2,source=4,filter=4,evaluation=4,share=8,io=20\")", configuration.ValidateStageLimits)
+	sourceCaps       = limitsFlag("source-workers", "worker caps per enumeration source (e.g. \"cache=1,disk1=2\")", nil)
+	targetCaps       = limitsFlag("target-workers", "worker caps per IO target, only for targets handling concurrent writes well (e.g. \"cache=2\")", nil)
+	globalBandwidth  = rateFlag("bwlimit", "bandwidth limit of all IO in bytes per second (e.g. \"50MiB\", 0 for no limit)")
+	sourceBandwidth  = ratesFlag("source-bwlimit", "bandwidth limits per IO source in bytes per second (e.g. \"cache=100MiB,disk1=20MiB\")")
+	targetBandwidth  = ratesFlag("target-bwlimit", "bandwidth limits per IO target in bytes per second (e.g. \"disk1=20MiB\")")
+	includePaths     = patternsFlag("include", "path patterns of the only files to move, as \"[share:]pattern\" (glob or \"re:\" regex), separated by semicolons or newlines")
+	excludePaths     = patternsFlag("exclude", "path patterns of files (or whole directories) not to move, as \"[share:]pattern\" (glob or \"re:\" regex), separated by semicolons or newlines")
+	moveOrder        = orderFlag("order", "comma-separated ordering policies of the queues (fifo, size-desc, size-asc, mtime, atime, share-priority, round-robin)")
+	sharePriority    = flag.String("share-priority", "", "comma-separated share names, the most important first (for the share-priority ordering policy)")
+	poolWatermarks   = watermarksFlag("watermarks", "move files off pools only above a high and until below a low usage watermark in percent (e.g. \"cache=85:65\", or \"*=85:65\" for all pools)")
+	evictBy          = choiceFlag("evict-by", eviction.ByAtime, []string{eviction.ByAtime, eviction.ByMtime}, "move the least recently used (\"atime\") or oldest (\"mtime\") files off pools above their watermarks first")
+	pipelineRules    = rulesFlag("rules", "pipeline rules as \"stage[:key] phase processor[(param=value, ...)]\", separated by semicolons or newlines")
+	hookRunPre       = flag.String("hook-run-pre", "", "shell script to run before the run, a failure fails the run")
+	hookRunPost      = flag.String("hook-run-post", "", "shell script to run after the run")
+	hookSharePre     = flag.String("hook-share-pre", "", "shell script to run before a share is enumerated, a failure skips the share")
+	hookSharePost    = flag.String("hook-share-post", "", "shell script to run after the files of a share were moved")
+	hookTargetPost   = flag.String("hook-target-post", "", "shell script to run after the files to a target storage were moved")
+	hookTimeout      = flag.Duration("hook-timeout", defaultHookTimeout, "time a hook script may run before it is killed")
+	ioniceClass      = choiceFlag("ionice-class", priority.ClassNone, []string{priority.ClassNone, priority.ClassBestEffort, priority.ClassIdle}, "IO scheduling class of the application and its hook scripts (\"best-effort\" or \"idle\", unchanged if empty)")
+	ioniceLevel      = flag.Int("ionice-level", defaultIOLevel, "IO priority level within the best-effort class, from 0 (highest) to 7 (lowest)")
+	niceness         = flag.Int("nice", 0, "CPU niceness of the application and its hook scripts, from -20 (highest) to 19 (lowest), unchanged if 0")
+	notifyEnabled    = flag.Bool("notify", true, "send Unraid notifications on completion and immediately on hash mismatches, out-of-space conditions and aborts")
+	notifyScript     = flag.String("notify-script", notify.DefaultScript, "path of the Unraid notify script")
+	intentLogPath    = flag.String("intent-log", "", "durably log the intents of all IO operations to this file (on persistent storage), for recovering after a crash")
+	journalPath      = flag.String("journal", "", "append every completed IO operation (as JSON Lines) to this journal file")
+	reportPath       = flag.String("report", "", "write the run summary report to this file")
+	reportFormat     = choiceFlag("report-format", report.FormatJSON, []string{report.FormatJSON, report.FormatText}, "format of the run summary report (\"json\" or \"text\")")
+
+	configPath = flag.String(configFlagName, defaultConfigPath, "read the options from this configuration file (KEY=value lines, keys named after the flags)")
+	logLevel   = choiceFlag("log-level", "debug", []string{"debug", "info", "warn", "error"}, "minimum level of the logs printed to the terminal or UI")
+	termLevel  = &slog.LevelVar{}
 )
 
 // termLogging enables or disables logs to be sent to the terminal (via
-// [os.Stdout]).
+// [os.Stdout], or [os.Stderr] if the standard output is reserved for results).
 func termLogging(enabled bool) {
 	if enabled {
 		if _, ok := slogMan.GetHandler("term"); !ok {
 			slogMan.AddHandler("term",
-				tint.NewHandler(os.Stdout,
+				tint.NewHandler(termOutput,
 					&tint.Options{
-						Level:      slog.LevelDebug,
+						Level:      termLevel,
 						TimeFormat: time.Kitchen,
 					}),
 			)
@@ -63,6 +156,14 @@ func termLogging(enabled bool) {
 	}
 }
 
+// redirectTermLogging redirects logs sent to the terminal to another
+// [os.File] (usually [os.Stderr], to keep [os.Stdout] clean for results).
+func redirectTermLogging(f *os.File) {
+	termLogging(false)
+	termOutput = f
+	termLogging(true)
+}
+
 // uiLogging enables or disables logs to be sent to a user interface (via
 // [ui.TeaLogWriter]).
 func uiLogging(enabled bool, writer *ui.TeaLogWriter) {
@@ -71,7 +172,7 @@ func uiLogging(enabled bool, writer *ui.TeaLogWriter) {
 			slogMan.AddHandler("ui",
 				tint.NewHandler(writer,
 					&tint.Options{
-						Level:      slog.LevelDebug,
+						Level:      termLevel,
 						TimeFormat: time.Kitchen,
 					}),
 			)
@@ -113,9 +214,160 @@ func setupSignalHandlers(cancel context.CancelFunc) {
 	}()
 }
 
+// parseCommand returns the requested command of the application and its
+// arguments, as resulting from the command-line arguments and flags. Any flags
+// following the command are parsed as well.
+func parseCommand() (string, []string, error) {
+	command := flag.Arg(0)
+
+	if command != "" {
+		// Allow for flags following the command (e.g. "plan -out plan.json").
+		_ = flag.CommandLine.Parse(flag.Args()[1:])
+	}
+
+	args := flag.Args()
+
+	switch command {
+	case "", cmdMove, cmdPlan:
+		if len(args) > 0 {
+			return "", nil, fmt.Errorf("%w: %v", ErrTooManyArgs, args)
+		}
+
+		if command == cmdPlan || *dryRun {
+			return cmdPlan, nil, nil
+		}
+
+		return cmdMove, nil, nil
+
+	case cmdApply:
+		if len(args) != 1 {
+			return "", nil, fmt.Errorf("%w: %s requires a plan file", ErrInvalidArgs, command)
+		}
+
+		return cmdApply, args, nil
+
+	case cmdDrain:
+		if len(args) != 1 {
+			return "", nil, fmt.Errorf("%w: %s requires a disk or pool", ErrInvalidArgs, command)
+		}
+
+		return cmdDrain, args, nil
+
+	case cmdSweep:
+		if len(args) > 0 {
+			return "", nil, fmt.Errorf("%w: %v", ErrTooManyArgs, args)
+		}
+
+		return cmdSweep, nil, nil
+
+	case cmdUndo:
+		if len(args) != 1 {
+			return "", nil, fmt.Errorf("%w: %s requires a run identifier", ErrInvalidArgs, command)
+		}
+
+		return cmdUndo, args, nil
+
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownCommand, command)
+	}
+}
+
+// setupOutput decides for a command how the standard output is to be used,
+// returning whether a UI should be launched and which (if any) progress format
+// should be printed. For a standard output that is not a terminal, the UI is
+// not used and a plain human-readable progress is printed instead.
+func setupOutput(command string) (bool, string) {
+	if command == cmdPlan {
+		// The plan is printed to standard output, so keep it clean for piping.
+		redirectTermLogging(os.Stderr)
+
+		return false, ""
+	}
+
+	stdoutIsTerminal := isTerminal(os.Stdout)
+
+	progressFormat := progressMode.String()
+	useUI := *uiEnabled && progressFormat == "" && stdoutIsTerminal
+
+	if progressFormat == "" && !useUI && !stdoutIsTerminal {
+		progressFormat = progress.FormatText
+	}
+
+	if progressFormat == progress.FormatJSON {
+		// The progress is printed to standard output, so keep it clean for piping.
+		redirectTermLogging(os.Stderr)
+	}
+
+	return useUI, progressFormat
+}
+
+// isTerminal returns whether a [os.File] is a terminal.
+func isTerminal(f *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS)
+
+	return err == nil
+}
+
+// startProgress is a helper function to start the application's progress
+// reporting (if one was requested for the application). The returned function
+// stops the progress reporting, waiting for a final report to be written.
+func startProgress(app *app) func() {
+	if app.progressReporter == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		app.progressReporter.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// startServices is a helper function to start the application's optional
+// services (API and metrics), as requested by the command-line flags. The
+// returned function stops all of the started services again.
+func startServices(ctx context.Context, cancel context.CancelFunc, app *app, memObserver *memoryObserver, apiLogBuffer *api.LogBuffer) (func(), error) {
+	stopFuncs := []func(){}
+
+	stopAll := func() {
+		for _, stop := range stopFuncs {
+			stop()
+		}
+	}
+
+	if *apiAddress != "" {
+		stopAPI, err := startAPI(ctx, cancel, app, *apiAddress, apiLogBuffer)
+		if err != nil {
+			stopAll()
+
+			return nil, err
+		}
+		stopFuncs = append(stopFuncs, stopAPI)
+	}
+
+	if *metricsAddress != "" || *metricsTextfile != "" {
+		stopMetrics, err := startMetrics(ctx, app, memObserver, *metricsAddress, *metricsTextfile)
+		if err != nil {
+			stopAll()
+
+			return nil, err
+		}
+		stopFuncs = append(stopFuncs, stopMetrics)
+	}
+
+	return stopAll, nil
+}
+
 // startApp is a helper function to start the application, waiting for the user
 // interface to come up or fail (if one was requested for the application).
-func startApp(ctx context.Context, wg *sync.WaitGroup, app *app) {
+func startApp(ctx context.Context, wg *sync.WaitGroup, app *app, command string, args []string) {
 	defer wg.Done()
 
 	if app.uiHandler != nil {
@@ -132,8 +384,49 @@ func startApp(ctx context.Context, wg *sync.WaitGroup, app *app) {
 		}
 	}
 
-	if err := app.Launch(ctx); err != nil {
-		exitCode = 1
+	stopProgress := startProgress(app)
+
+	startedAt := time.Now()
+
+	stopAbortWatch := app.watchAbort(ctx)
+
+	err := app.runHook(ctx, &hooks.Event{Hook: hooks.RunPre, Command: command})
+	if err == nil {
+		err = launchCommand(ctx, app, command, args)
+	}
+
+	stopAbortWatch()
+	stopProgress()
+
+	app.runSharePostHooks(ctx)
+
+	r := report.New(command, startedAt, app.queueManager, app.reportCollector, err)
+	app.runRunPostHook(ctx, r)
+	app.notifyReport(ctx, r)
+
+	exitCode = app.finishReport(r, err)
+}
+
+// launchCommand is a helper function to launch the application for a command.
+func launchCommand(ctx context.Context, app *app, command string, args []string) error {
+	switch command {
+	case cmdPlan:
+		return app.LaunchPlan(ctx, os.Stdout, *jsonOutput, *planOut)
+
+	case cmdApply:
+		return app.LaunchApply(ctx, args[0])
+
+	case cmdDrain:
+		return app.LaunchDrain(ctx, args[0])
+
+	case cmdSweep:
+		return app.LaunchSweep(ctx, *dryRun, *sweepNext)
+
+	case cmdUndo:
+		return app.LaunchUndo(ctx, *journalPath, args[0])
+
+	default:
+		return app.Launch(ctx)
 	}
 }
 
@@ -181,11 +474,204 @@ func startUI(ctx context.Context, wg *sync.WaitGroup, app *app) {
 	}
 }
 
+// setupApp is a helper function to establish all handlers and the Unraid
+// system, returning the [app] that is ready to be launched for a command. The
+// intent log and the journal are only opened for a command holding the
+// single-instance lock (locked), as another instance may be using them.
+func setupApp(ctx context.Context, cancel context.CancelFunc, locked bool, useUI bool, progressFormat string) (*app, error) {
+	osProvider := &schema.OS{}
+	unixProvider := &schema.Unix{}
+	configProvider := &configuration.GodotenvProvider{}
+
+	fsHandler, err := filesystem.NewHandler(ctx, osProvider, unixProvider)
+	if err != nil {
+		return nil, fmt.Errorf("(main) failed to establish filesystem handler: %w", err)
+	}
+
+	allocHandler := allocation.NewHandler(fsHandler)
+	pathingHandler := pathing.NewHandler(osProvider)
+	intentLog, journalWriter, err := openLogs(locked)
+	if err != nil {
+		return nil, fmt.Errorf("(main) failed to establish logs: %w", err)
+	}
+
+	limiter := throttle.NewLimiter(throttle.Limits{
+		Global:  globalBandwidth.rate,
+		Sources: sourceBandwidth.rates,
+		Targets: targetBandwidth.rates,
+	})
+
+	ioHandler := io.NewHandler(fsHandler, osProvider, unixProvider, intentLog, journalWriter, limiter)
+	configHandler := configuration.NewHandler(configProvider)
+	unraidHandler := unraid.NewHandler(fsHandler, configHandler, osProvider)
+
+	system, err := unraidHandler.EstablishSystem()
+	if err != nil {
+		return nil, fmt.Errorf("(main) failed to establish (parts of) the unraid system: %w", err)
+	}
+
+	shares := system.GetShares()
+	storages := system.GetStorages()
+	queueManager := queue.NewManager()
+
+	var uiHandler *ui.Handler
+	if useUI {
+		uiHandler = ui.NewHandler(ctx, cancel, queueManager)
+	}
+
+	var progressReporter *progress.Reporter
+	if progressFormat != "" {
+		progressReporter, err = progress.NewReporter(queueManager, os.Stdout, progressFormat, *progressInterval)
+		if err != nil {
+			return nil, fmt.Errorf("(main) failed to establish progress reporter: %w", err)
+		}
+	}
+
+	shareAdapters := make(map[string]schema.Share, len(shares))
+	for name, share := range shares {
+		shareAdapters[name] = newShareAdapter(share)
+	}
+
+	reportCollector := report.NewCollector(errorClasses)
+
+	app := newApp(shareAdapters, storages, queueManager, fsHandler, allocHandler, pathingHandler, ioHandler, uiHandler, progressReporter, reportCollector)
+	app.intentLog = intentLog
+	app.journalWriter = journalWriter
+	app.limiter = limiter
+
+	if err := setupConcurrency(app.config.Concurrency); err != nil {
+		return nil, fmt.Errorf("(main) failed to establish worker limits: %w", err)
+	}
+
+	storageNames := slices.Sorted(maps.Keys(storages))
+	shareNames := slices.Sorted(maps.Keys(shares))
+
+	app.config.Ordering = processors.NewOrdering(moveOrder.policies, strings.FieldsFunc(*sharePriority, func(r rune) bool { return r == ',' || r == ' ' }))
+
+	if err := setupPaths(app.config.Paths, shareNames); err != nil {
+		return nil, fmt.Errorf("(main) failed to establish path patterns: %w", err)
+	}
+
+	if err := processors.CompileRules(pipelineRules.rules, storageNames, shareNames, storageNames, app.config.Pipelines); err != nil {
+		return nil, fmt.Errorf("(main) failed to establish pipeline rules: %w", err)
+	}
+
+	app.setupEviction(shareNames, storageNames)
+
+	return app, nil
+}
+
+// openLogs is a helper function to open the intent log and the journal of the
+// run, as requested by the (layered) options. Unless locked, both discard all
+// of their records, with neither file being opened (nor emptied on closing).
+func openLogs(locked bool) (*intent.Log, *journal.Writer, error) {
+	if !locked {
+		intentLog, _ := intent.Open("")
+		journalWriter, _ := journal.Open("", journal.NewRunID())
+
+		return intentLog, journalWriter, nil
+	}
+
+	intentLog, err := intent.Open(*intentLogPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("(main) failed to open intent log: %w", err)
+	}
+
+	journalWriter, err := journal.Open(*journalPath, journal.NewRunID())
+	if err != nil {
+		_ = intentLog.Close()
+
+		return nil, nil, fmt.Errorf("(main) failed to open journal: %w", err)
+	}
+
+	return intentLog, journalWriter, nil
+}
+
+// schedSettings returns the [priority.Settings], as requested by the (layered)
+// options.
+func schedSettings() priority.Settings {
+	return priority.Settings{
+		IOClass: ioniceClass.String(),
+		IOLevel: *ioniceLevel,
+		Nice:    *niceness,
+	}
+}
+
+// setupPriority is a helper function to set the scheduling priorities of the
+// application (all of its threads), as requested by the (layered) options.
+func setupPriority() error {
+	settings := schedSettings()
+
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("(main) %w", err)
+	}
+
+	if err := settings.Apply(); err != nil {
+		return fmt.Errorf("(main) %w", err)
+	}
+
+	if !settings.IsZero() {
+		slog.Debug("Scheduling priorities set:",
+			"ioClass", settings.IOClass,
+			"ioLevel", settings.IOLevel,
+			"nice", settings.Nice,
+		)
+	}
+
+	return nil
+}
+
+// setupPaths is a helper function to establish the [filesystem.PathFilter] of
+// all shares with path patterns, as requested by the (layered) options.
+func setupPaths(paths map[string]*filesystem.PathFilter, shareNames []string) error {
+	for _, patterns := range []*patternsValue{includePaths, excludePaths} {
+		for name := range patterns.patterns {
+			if name != filesystem.AllShares && !slices.Contains(shareNames, name) {
+				slog.Warn("Skipped path patterns: no matching share",
+					"share", name,
+				)
+			}
+		}
+	}
+
+	for _, name := range shareNames {
+		includes := includePaths.forShare(name)
+		excludes := excludePaths.forShare(name)
+
+		if len(includes) == 0 && len(excludes) == 0 {
+			continue
+		}
+
+		filter, err := filesystem.NewPathFilter(includes, excludes)
+		if err != nil {
+			return err
+		}
+		paths[name] = filter
+	}
+
+	return nil
+}
+
+// setupConcurrency is a helper function to set the worker limits of a
+// [configuration.ConcurrencyConfiguration], as requested by the (layered)
+// options.
+func setupConcurrency(c *configuration.ConcurrencyConfiguration) error {
+	if err := c.SetStageLimits(workerLimits.limits); err != nil {
+		return err
+	}
+
+	c.SourceCaps = maps.Clone(sourceCaps.limits)
+	c.TargetCaps = maps.Clone(targetCaps.limits)
+
+	return nil
+}
+
 func main() {
 	defer func() {
 		os.Exit(exitCode)
 	}()
 
+	termLevel.Set(slog.LevelDebug)
 	slog.SetDefault(slog.New(slogMan))
 	termLogging(true)
 
@@ -193,8 +679,66 @@ func main() {
 	defer cancel()
 
 	flag.Parse()
+	lock := newInstanceLock(lockFilePath, moverPIDFilePath)
+	defer lock.Release()
+
 	setupSignalHandlers(cancel)
 
+	run(ctx, cancel, lock)
+}
+
+// run is the principal function running the application for the requested
+// command, once the fundamentals (logging, flags, signals) are established.
+func run(ctx context.Context, cancel context.CancelFunc, lock *instanceLock) {
+	command, args, err := parseCommand()
+	if err == nil {
+		err = loadConfig()
+	}
+	if err != nil {
+		slog.Error("Invalid command-line arguments or configuration.",
+			"err", err,
+		)
+		exitCode = exitFatal
+
+		return
+	}
+
+	if err := setupPriority(); err != nil {
+		slog.Error("Failed to set the scheduling priorities.",
+			"err", err,
+		)
+		exitCode = exitFatal
+
+		return
+	}
+
+	useUI, progressFormat := setupOutput(command)
+
+	if command != cmdPlan {
+		if err := lock.Acquire(ctx, *waitLock); err != nil {
+			slog.Error("Failed to acquire the single-instance lock.",
+				"err", err,
+			)
+			exitCode = exitFatal
+
+			return
+		}
+
+		if err := recoverIntents(); err != nil {
+			slog.Error("Failed to recover from the intent log.",
+				"err", err,
+			)
+			exitCode = exitFatal
+
+			return
+		}
+	}
+
+	var apiLogBuffer *api.LogBuffer
+	if *apiAddress != "" {
+		apiLogBuffer = newAPILogBuffer()
+	}
+
 	memObserver := newMemoryObserver(ctx)
 	defer memObserver.Stop()
 
@@ -204,55 +748,57 @@ func main() {
 	allocProfiler := newAllocProfiler(ctx, memprofile)
 	defer allocProfiler.Stop()
 
-	osProvider := &schema.OS{}
-	unixProvider := &schema.Unix{}
-	configProvider := &configuration.GodotenvProvider{}
-
-	fsHandler, err := filesystem.NewHandler(ctx, osProvider, unixProvider)
+	app, err := setupApp(ctx, cancel, command != cmdPlan, useUI, progressFormat)
 	if err != nil {
-		slog.Error("Failed to establish filesystem handler.",
+		slog.Error("Failed to establish the application.",
 			"err", err,
 		)
+		exitCode = exitFatal
 
 		return
 	}
 
-	allocHandler := allocation.NewHandler(fsHandler)
-	pathingHandler := pathing.NewHandler(osProvider)
-	ioHandler := io.NewHandler(fsHandler, osProvider, unixProvider)
-	configHandler := configuration.NewHandler(configProvider)
-	unraidHandler := unraid.NewHandler(fsHandler, configHandler, osProvider)
+	if command != cmdPlan {
+		if command != cmdSweep {
+			app.setupHooks()
+		}
+		app.setupNotify()
+	}
 
-	system, err := unraidHandler.EstablishSystem()
+	defer func() {
+		if err := app.journalWriter.Close(); err != nil {
+			slog.Error("Failed to close the journal.",
+				"err", err,
+			)
+		}
+		if err := app.intentLog.Close(); err != nil {
+			slog.Error("Failed to close the intent log.",
+				"err", err,
+			)
+		}
+	}()
+
+	stopReload := app.watchBandwidthReload(ctx)
+	defer stopReload()
+
+	stopServices, err := startServices(ctx, cancel, app, memObserver, apiLogBuffer)
 	if err != nil {
-		slog.Error("Failed to establish (parts of) the Unraid system.",
+		slog.Error("Failed to establish the services.",
 			"err", err,
 		)
+		exitCode = exitFatal
 
 		return
 	}
-
-	shares := system.GetShares()
-	queueManager := queue.NewManager()
-
-	var uiHandler *ui.Handler
-	if uiEnabled != nil && *uiEnabled {
-		uiHandler = ui.NewHandler(ctx, cancel, queueManager)
-	}
-
-	shareAdapters := make(map[string]schema.Share, len(shares))
-	for name, share := range shares {
-		shareAdapters[name] = newShareAdapter(share)
-	}
+	defer stopServices()
 
 	var wg sync.WaitGroup
-	app := newApp(shareAdapters, queueManager, fsHandler, allocHandler, pathingHandler, ioHandler, uiHandler)
 
 	wg.Add(1)
 	go startUI(ctx, &wg, app)
 
 	wg.Add(1)
-	go startApp(ctx, &wg, app)
+	go startApp(ctx, &wg, app, command, args)
 
 	wg.Wait()
 }
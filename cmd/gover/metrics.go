@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/desertwitch/gover/internal/api"
+	"github.com/desertwitch/gover/internal/metrics"
+)
+
+const (
+	// metricsTextfileInterval is the interval at which the metrics textfile is
+	// written.
+	metricsTextfileInterval = 10 * time.Second
+)
+
+// startMetrics is a helper function to start the exposition of the
+// application's metrics, served on a given (local) address and/or written to a
+// given textfile path. The returned function stops the exposition again, with
+// a textfile written a last (final) time.
+func startMetrics(ctx context.Context, app *app, memObserver *memoryObserver, address string, textfilePath string) (func(), error) {
+	metricsHandler := metrics.NewHandler(app.queueManager, app.storages, app.fsHandler, memObserver)
+
+	metricsCtx, metricsCancel := context.WithCancel(context.Background())
+	done := make(chan struct{}, 2) //nolint:mnd
+	running := 0
+
+	if address != "" {
+		listener, err := api.Listen(address)
+		if err != nil {
+			metricsCancel()
+
+			return nil, fmt.Errorf("(main-metrics) %w", err)
+		}
+
+		running++
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			if err := metricsHandler.Serve(metricsCtx, listener); err != nil {
+				slog.Error("Metrics failure.",
+					"err", err,
+				)
+			}
+		}()
+
+		slog.Info("Serving metrics:",
+			"address", listener.Addr().String(),
+		)
+	}
+
+	if textfilePath != "" {
+		running++
+		go func() {
+			defer func() { done <- struct{}{} }()
+			metricsHandler.RunTextfile(metricsCtx, textfilePath, metricsTextfileInterval)
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		metricsCancel()
+	}()
+
+	return func() {
+		metricsCancel()
+		for range running {
+			<-done
+		}
+	}, nil
+}
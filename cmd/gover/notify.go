@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/desertwitch/gover/internal/notify"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/report"
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/dustin/go-humanize"
+)
+
+// setupNotify is a helper function to establish the [notify.Handler] of the
+// [app], as requested by the (layered) options. The errors of all items of the
+// IO are checked by it as they are recorded by their queues. Without a notify
+// script (e.g. outside of Unraid), no notifications are sent.
+func (app *app) setupNotify() {
+	if !*notifyEnabled {
+		return
+	}
+
+	if _, err := os.Stat(*notifyScript); err != nil {
+		slog.Warn("Notifications disabled: notify script not found",
+			"path", *notifyScript,
+		)
+
+		return
+	}
+
+	app.notifyHandler = notify.NewHandler(*notifyScript)
+
+	app.queueManager.IOManager.SetErrorFunc(func(m *schema.Moveable, err error) {
+		app.notifyHandler.Check(m.SourcePath, err)
+	})
+}
+
+// watchAbort sends a [notify.Notification] as soon as the given context is
+// cancelled (aborting the run), until the returned function is called once the
+// run has finished. Without a [notify.Handler], this function is a no-op.
+func (app *app) watchAbort(ctx context.Context) func() {
+	if app.notifyHandler == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		select {
+		case <-ctx.Done():
+			app.notifyHandler.SendOnce(notify.Notification{
+				Subject:     "Run aborted",
+				Description: "The run was aborted before it has finished.",
+				Importance:  notify.ImportanceAlert,
+			})
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// notifyReport sends the summary of the [report.Report] of a finished run as
+// [notify.Notification], with the importance derived from its
+// [report.Outcome]. Without a [notify.Handler], this function is a no-op.
+func (app *app) notifyReport(ctx context.Context, r *report.Report) {
+	if app.notifyHandler == nil {
+		return
+	}
+
+	app.notifyHandler.Wait()
+
+	var importance string
+	switch r.Outcome {
+	case report.OutcomeSuccess:
+		importance = notify.ImportanceNormal
+	case report.OutcomePartial:
+		importance = notify.ImportanceWarning
+	default:
+		importance = notify.ImportanceAlert
+	}
+
+	var success, items int
+	var bytes uint64
+	if ioStage := r.Stage(progress.StageIO); ioStage != nil {
+		success, items, bytes = ioStage.Success, ioStage.Items, ioStage.Bytes
+	}
+
+	description := fmt.Sprintf("Moved %d of %d files (%s), with %d skipped and %d errors.",
+		success, items, humanize.IBytes(bytes), r.TotalSkipped(), r.TotalErrors())
+	if r.Fatal != "" {
+		description += " " + r.Fatal
+	}
+
+	if err := app.notifyHandler.Send(ctx, notify.Notification{
+		Subject:     fmt.Sprintf("Run of %s finished: %s", r.Command, r.Outcome),
+		Description: description,
+		Importance:  importance,
+	}); err != nil {
+		slog.Error("Failed to send notification.",
+			"err", err,
+		)
+	}
+}
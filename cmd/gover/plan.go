@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/desertwitch/gover/internal/plan"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// Plan is the principal method for a dry-run of the application. It runs both
+// the enumeration and evaluation, but instead of any IO happening it returns a
+// [plan.Plan] describing what the IO would do with the [queue.IOManager]'s
+// current target queues.
+func (app *app) Plan(ctx context.Context) (*plan.Plan, error) {
+	if err := app.Enumerate(ctx); err != nil {
+		return nil, fmt.Errorf("(app-plan) %w", err)
+	}
+
+	app.selectEvictions()
+
+	if err := app.Evaluate(ctx); err != nil {
+		return nil, fmt.Errorf("(app-plan) %w", err)
+	}
+
+	targets := make(map[schema.Storage][]*schema.Moveable)
+
+	for target, targetQueue := range app.queueManager.IOManager.GetQueues() {
+		if app.config.Ordering != nil {
+			targetQueue.Reorder(app.config.Ordering)
+		}
+
+		targets[target] = targetQueue.GetItems()
+	}
+
+	return plan.New(targets), nil
+}
+
+// LaunchPlan starts the application as a dry-run, writing the resulting
+// [plan.Plan] to the given [io.Writer] (either as a human-readable table or as
+// machine-readable JSON). If an output path is given, the [plan.Plan] is also
+// persisted there, for later use with [app.LaunchApply].
+func (app *app) LaunchPlan(ctx context.Context, w io.Writer, asJSON bool, outPath string) error {
+	p, err := app.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	if outPath != "" {
+		if err := writePlanFile(p, outPath); err != nil {
+			return fmt.Errorf("(app) %w", err)
+		}
+	}
+
+	if asJSON {
+		err = p.WriteJSON(w)
+	} else {
+		err = p.WriteTable(w)
+	}
+
+	if err != nil {
+		return fmt.Errorf("(app) failed to output plan: %w", err)
+	}
+
+	return nil
+}
+
+// writePlanFile persists a [plan.Plan] as JSON to the given path.
+func writePlanFile(p *plan.Plan, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("(app-plan) failed to create: %w", err)
+	}
+
+	if err := p.WriteJSON(f); err != nil {
+		f.Close()
+
+		return fmt.Errorf("(app-plan) %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("(app-plan) failed to close: %w", err)
+	}
+
+	slog.Info("Plan written:",
+		"path", path,
+	)
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/intent"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// recoverIntents is a helper function to replay the intent log (if any was
+// requested) left behind by a previous run that did not finish (e.g. due to a
+// loss of power), before any new work begins.
+func recoverIntents() error {
+	if *intentLogPath == "" {
+		return nil
+	}
+
+	recovery, err := intent.NewHandler(&schema.OS{}, &schema.Unix{}).Recover(*intentLogPath)
+	if err != nil {
+		return fmt.Errorf("(main) %w", err)
+	}
+
+	if recovery.Fixed() == 0 && recovery.Failed == 0 {
+		return nil
+	}
+
+	slog.Info("Recovered from intent log:",
+		"finished", recovery.Finished,
+		"rolledBack", recovery.RolledBack,
+		"tempsRemoved", recovery.TempsRemoved,
+		"dirsRemoved", recovery.DirsRemoved,
+		"failed", recovery.Failed,
+	)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/desertwitch/gover/internal/allocation"
+	"github.com/desertwitch/gover/internal/eviction"
+	"github.com/desertwitch/gover/internal/hooks"
+	"github.com/desertwitch/gover/internal/io"
+	"github.com/desertwitch/gover/internal/journal"
+	"github.com/desertwitch/gover/internal/pathing"
+	"github.com/desertwitch/gover/internal/plan"
+	"github.com/desertwitch/gover/internal/processors"
+	"github.com/desertwitch/gover/internal/report"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// exitSuccess is the exit code for a run where everything was moved.
+	exitSuccess = 0
+
+	// exitFatal is the exit code for a run that has failed as a whole.
+	exitFatal = 1
+
+	// exitPartial is the exit code for a run that has completed, but with
+	// items that were skipped (other than filtered) or errors that occurred.
+	exitPartial = 2
+)
+
+// errorClasses are all known error classes of the application, in the order
+// they are matched by the [report.Collector].
+var errorClasses = []report.ErrorClass{
+	{Name: "io.ErrNotEnoughSpace", Err: io.ErrNotEnoughSpace},
+	{Name: "io.ErrHashMismatch", Err: io.ErrHashMismatch},
+	{Name: "io.ErrSourceFileInUse", Err: io.ErrSourceFileInUse},
+	{Name: "io.ErrRenameExists", Err: io.ErrRenameExists},
+	{Name: "io.ErrNothingToProcess", Err: io.ErrNothingToProcess},
+	{Name: "pathing.ErrPathExistsOnDest", Err: pathing.ErrPathExistsOnDest},
+	{Name: "pathing.ErrSourceIsRelative", Err: pathing.ErrSourceIsRelative},
+	{Name: "allocation.ErrNotAllocatable", Err: allocation.ErrNotAllocatable},
+	{Name: "allocation.ErrNoAllocationMethod", Err: allocation.ErrNoAllocationMethod},
+	{Name: "allocation.ErrNoDiskStats", Err: allocation.ErrNoDiskStats},
+	{Name: "plan.ErrSourceChanged", Err: plan.ErrSourceChanged},
+	{Name: "plan.ErrSourceVanished", Err: plan.ErrSourceVanished},
+	{Name: "plan.ErrNoMetadata", Err: plan.ErrNoMetadata},
+	{Name: "journal.ErrDestChanged", Err: journal.ErrDestChanged},
+	{Name: "processors.ErrFilteredByGlob", Err: processors.ErrFilteredByGlob, Filter: true},
+	{Name: "processors.ErrFilteredBySize", Err: processors.ErrFilteredBySize, Filter: true},
+	{Name: "processors.ErrFilteredByAge", Err: processors.ErrFilteredByAge, Filter: true},
+	{Name: "eviction.ErrLowWatermarkReached", Err: eviction.ErrLowWatermarkReached, Filter: true},
+	{Name: "hooks.ErrHookFailed", Err: hooks.ErrHookFailed},
+	{Name: "unix.ENOSPC", Err: unix.ENOSPC},
+	{Name: "unix.EIO", Err: unix.EIO},
+	{Name: "fs.ErrNotExist", Err: fs.ErrNotExist},
+	{Name: "fs.ErrPermission", Err: fs.ErrPermission},
+	{Name: "context.Canceled", Err: context.Canceled},
+}
+
+// finishReport logs the summary of the [report.Report] of a finished run and
+// persists it (if requested), returning the exit code that corresponds to its
+// [report.Outcome].
+func (app *app) finishReport(r *report.Report, runErr error) int {
+	if runErr != nil {
+		slog.Error("Run failed:",
+			"err", runErr,
+			"command", r.Command,
+		)
+	}
+
+	slog.Info("Run finished:",
+		"runId", app.journalWriter.RunID(),
+		"outcome", r.Outcome,
+		"skipped", r.TotalSkipped(),
+		"filtered", r.TotalFiltered(),
+		"errors", r.TotalErrors(),
+		"duration", r.FinishedAt.Sub(r.StartedAt).Round(time.Second),
+	)
+
+	if *reportPath != "" {
+		if err := writeReportFile(r, *reportPath, reportFormat.String()); err != nil {
+			slog.Error("Failed to write the report.",
+				"err", err,
+			)
+		}
+	}
+
+	switch r.Outcome {
+	case report.OutcomeSuccess:
+		return exitSuccess
+
+	case report.OutcomePartial:
+		return exitPartial
+
+	default:
+		return exitFatal
+	}
+}
+
+// writeReportFile persists a [report.Report] in the given format to the given
+// path.
+func writeReportFile(r *report.Report, path string, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("(app-report) failed to create: %w", err)
+	}
+
+	if err := r.Write(f, format); err != nil {
+		f.Close()
+
+		return fmt.Errorf("(app-report) %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("(app-report) failed to close: %w", err)
+	}
+
+	slog.Info("Report written:",
+		"path", path,
+	)
+
+	return nil
+}
@@ -2,10 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"runtime"
 
+	"github.com/desertwitch/gover/internal/progress"
 	"github.com/desertwitch/gover/internal/queue"
 	"github.com/desertwitch/gover/internal/schema"
 )
@@ -23,13 +24,17 @@ func (app *app) Enumerate(ctx context.Context) error {
 			continue
 		}
 
+		if !app.runSharePreHook(ctx, share, share.GetCachePool(), share.GetCachePool2()) {
+			continue
+		}
+
 		if share.GetCachePool2() == nil {
 			// Cache to Array
 			app.queueManager.EnumerationManager.Enqueue(&queue.EnumerationTask{
 				Share:  share,
 				Source: share.GetCachePool(),
-				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() int {
-					return func() int {
+				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() (int, error) {
+					return func() (int, error) {
 						return app.enumerateToEvaluation(ctx, share, src, dst)
 					}
 				}(share, share.GetCachePool(), nil),
@@ -39,8 +44,8 @@ func (app *app) Enumerate(ctx context.Context) error {
 			app.queueManager.EnumerationManager.Enqueue(&queue.EnumerationTask{
 				Share:  share,
 				Source: share.GetCachePool(),
-				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() int {
-					return func() int {
+				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() (int, error) {
+					return func() (int, error) {
 						return app.enumerateToEvaluation(ctx, share, src, dst)
 					}
 				}(share, share.GetCachePool(), share.GetCachePool2()),
@@ -54,14 +59,18 @@ func (app *app) Enumerate(ctx context.Context) error {
 			continue
 		}
 
+		if !app.runSharePreHook(ctx, share, share.GetCachePool2(), share.GetCachePool()) {
+			continue
+		}
+
 		if share.GetCachePool2() == nil {
 			// Array to Cache
 			for _, disk := range share.GetIncludedDisks() {
 				app.queueManager.EnumerationManager.Enqueue(&queue.EnumerationTask{
 					Share:  share,
 					Source: disk,
-					Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() int {
-						return func() int {
+					Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() (int, error) {
+						return func() (int, error) {
 							return app.enumerateToEvaluation(ctx, share, src, dst)
 						}
 					}(share, disk, share.GetCachePool()),
@@ -72,8 +81,8 @@ func (app *app) Enumerate(ctx context.Context) error {
 			app.queueManager.EnumerationManager.Enqueue(&queue.EnumerationTask{
 				Share:  share,
 				Source: share.GetCachePool2(),
-				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() int {
-					return func() int {
+				Function: func(share schema.Share, src schema.Storage, dst schema.Storage) func() (int, error) {
+					return func() (int, error) {
 						return app.enumerateToEvaluation(ctx, share, src, dst)
 					}
 				}(share, share.GetCachePool2(), share.GetCachePool()),
@@ -84,12 +93,14 @@ func (app *app) Enumerate(ctx context.Context) error {
 	for source, sourceQueue := range app.queueManager.EnumerationManager.GetQueues() {
 		tasker.Add(func(source schema.Storage, sourceQueue *queue.EnumerationSourceQueue) func() {
 			return func() {
-				_ = app.processEnumerationQueue(ctx, source, sourceQueue)
+				if err := app.processEnumerationQueue(ctx, source, sourceQueue); err != nil {
+					app.reportCollector.AddFailure(progress.StageEnumeration, source.GetName(), err)
+				}
 			}
 		}(source, sourceQueue))
 	}
 
-	if err := tasker.LaunchConcAndWait(ctx, runtime.NumCPU()); err != nil {
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.Enumeration()); err != nil {
 		return fmt.Errorf("(app-enum) %w", err)
 	}
 
@@ -100,8 +111,9 @@ func (app *app) Enumerate(ctx context.Context) error {
 // (which are [queue.EnumerationTask] of one specific source [schema.Storage])
 // and runs their contained enumeration functions concurrently. This means
 // multiple [schema.Share] on one source [schema.Storage] are read for their
-// [schema.Moveable] at the same time.
-func (app *app) processEnumerationQueue(ctx context.Context, source schema.Storage, sourceQueue *queue.EnumerationSourceQueue) bool {
+// [schema.Moveable] at the same time. An error is only returned if the
+// [queue.EnumerationSourceQueue] has failed as a whole.
+func (app *app) processEnumerationQueue(ctx context.Context, source schema.Storage, sourceQueue *queue.EnumerationSourceQueue) error {
 	slog.Info("Enumerating shares on source:",
 		"source", source.GetName(),
 	)
@@ -112,14 +124,14 @@ func (app *app) processEnumerationQueue(ctx context.Context, source schema.Stora
 				"source", source.GetName(),
 			)
 
-			return false
+			return fmt.Errorf("(app-enum) %w", ErrPipePreProcFailed)
 		}
 	}
 
-	if err := sourceQueue.DequeueAndProcessConc(ctx, runtime.NumCPU(), func(enumTask *queue.EnumerationTask) int {
+	if err := sourceQueue.DequeueAndProcessConc(ctx, app.config.Concurrency.Source(source.GetName()), func(enumTask *queue.EnumerationTask) (int, error) {
 		if pipeline, exists := app.config.Pipelines.EnumerationPipelines[source.GetName()]; exists {
-			if success := pipeline.Process(enumTask); !success {
-				return queue.DecisionSkipped
+			if err := pipeline.Process(enumTask); err != nil {
+				return queue.DecisionSkipped, err
 			}
 		}
 
@@ -130,7 +142,7 @@ func (app *app) processEnumerationQueue(ctx context.Context, source schema.Stora
 			"source", source.GetName(),
 		)
 
-		return false
+		return fmt.Errorf("(app-enum) %w", err)
 	}
 
 	if pipeline, exists := app.config.Pipelines.EnumerationPipelines[source.GetName()]; exists {
@@ -139,7 +151,7 @@ func (app *app) processEnumerationQueue(ctx context.Context, source schema.Stora
 				"source", source.GetName(),
 			)
 
-			return false
+			return fmt.Errorf("(app-enum) %w", ErrPipePostProcFailed)
 		}
 	}
 
@@ -147,19 +159,19 @@ func (app *app) processEnumerationQueue(ctx context.Context, source schema.Stora
 		"share", source.GetName(),
 	)
 
-	return true
+	return nil
 }
 
 // enumerateToEvaluation is the actual given to [queue.EnumerationTask] function
 // that collects all [schema.Moveable] for a [schema.Share] on a specific source
 // [schema.Storage] and enqueues the results into the [queue.EvaluationManager].
-func (app *app) enumerateToEvaluation(ctx context.Context, share schema.Share, src schema.Storage, dst schema.Storage) int {
+func (app *app) enumerateToEvaluation(ctx context.Context, share schema.Share, src schema.Storage, dst schema.Storage) (int, error) {
 	slog.Info("Enumerating share on storage:",
 		"storage", src.GetName(),
 		"share", share.GetName(),
 	)
 
-	files, err := app.fsHandler.GetMoveables(ctx, share, src, dst)
+	files, skipped, err := app.fsHandler.GetMoveables(ctx, share, src, dst, app.config.Paths[share.GetName()], app.config.Concurrency.Filter(src.GetName()))
 	if err != nil {
 		slog.Warn("Skipped enumerating share on storage due to failure:",
 			"err", err,
@@ -167,7 +179,7 @@ func (app *app) enumerateToEvaluation(ctx context.Context, share schema.Share, s
 			"share", share.GetName(),
 		)
 
-		return queue.DecisionSkipped
+		return queue.DecisionSkipped, err
 	}
 
 	slog.Info("Enumerating shares on storage done:",
@@ -177,5 +189,5 @@ func (app *app) enumerateToEvaluation(ctx context.Context, share schema.Share, s
 
 	app.queueManager.EvaluationManager.Enqueue(files...)
 
-	return queue.DecisionSuccess
+	return queue.DecisionSuccess, errors.Join(skipped...)
 }
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"runtime"
 
+	"github.com/desertwitch/gover/internal/progress"
 	"github.com/desertwitch/gover/internal/queue"
 	"github.com/desertwitch/gover/internal/schema"
 	"github.com/desertwitch/gover/internal/validation"
@@ -22,13 +22,15 @@ func (app *app) Evaluate(ctx context.Context) error {
 		tasker.Add(
 			func(share schema.Share, shareQueue *queue.EvaluationShareQueue) func() {
 				return func() {
-					_ = app.processEvaluationQueue(ctx, share, shareQueue)
+					if err := app.processEvaluationQueue(ctx, share, shareQueue); err != nil {
+						app.reportCollector.AddFailure(progress.StageEvaluation, share.GetName(), err)
+					}
 				}
 			}(share, shareQueue),
 		)
 	}
 
-	if err := tasker.LaunchConcAndWait(ctx, runtime.NumCPU()); err != nil {
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.Evaluation()); err != nil {
 		return fmt.Errorf("(app-eval) %w", err)
 	}
 
@@ -36,8 +38,9 @@ func (app *app) Evaluate(ctx context.Context) error {
 }
 
 // processEvaluationQueue processes an [queue.EvaluationShareQueue]'s items
-// (which are [schema.Moveable] of one specific [schema.Share]).
-func (app *app) processEvaluationQueue(ctx context.Context, share schema.Share, shareQueue *queue.EvaluationShareQueue) bool {
+// (which are [schema.Moveable] of one specific [schema.Share]). An error is
+// only returned if the [queue.EvaluationShareQueue] has failed as a whole.
+func (app *app) processEvaluationQueue(ctx context.Context, share schema.Share, shareQueue *queue.EvaluationShareQueue) error {
 	slog.Info("Evaluating share:",
 		"share", share.GetName(),
 	)
@@ -48,14 +51,14 @@ func (app *app) processEvaluationQueue(ctx context.Context, share schema.Share,
 			"share", share.GetName(),
 		)
 
-		return false
+		return err
 	}
 
 	slog.Info("Evaluating share done:",
 		"share", share.GetName(),
 	)
 
-	return true
+	return nil
 }
 
 // evaluateToIO is the processing logic for an [queue.EvaluationShareQueue]. It
@@ -63,34 +66,38 @@ func (app *app) processEvaluationQueue(ctx context.Context, share schema.Share,
 // that multiple [schema.Moveable] of one specific [schema.Share] are processed
 // at the same time.
 func (app *app) evaluateToIO(ctx context.Context, share schema.Share, q *queue.EvaluationShareQueue) error {
+	if app.config.Ordering != nil {
+		q.Reorder(app.config.Ordering)
+	}
+
 	if pipeline, exists := app.config.Pipelines.EvaluationPipelines[share.GetName()]; exists {
 		if success := q.PreProcess(pipeline); !success {
 			return fmt.Errorf("(app-eval) %w", ErrPipePreProcFailed)
 		}
 	}
 
-	if err := q.DequeueAndProcessConc(ctx, runtime.NumCPU(), func(m *schema.Moveable) int {
+	if err := q.DequeueAndProcessConc(ctx, app.config.Concurrency.Share(), func(m *schema.Moveable) (int, error) {
 		if pipeline, exists := app.config.Pipelines.EvaluationPipelines[share.GetName()]; exists {
-			if success := pipeline.Process(m); !success {
-				return queue.DecisionSkipped
+			if err := pipeline.Process(m); err != nil {
+				return queue.DecisionSkipped, err
 			}
 		}
 
 		if m.Dest == nil {
-			if success := app.allocHandler.AllocateArrayDestination(m); !success {
-				return queue.DecisionSkipped
+			if err := app.allocHandler.AllocateArrayDestination(m); err != nil {
+				return queue.DecisionSkipped, err
 			}
 		}
 
-		if success := app.pathingHandler.EstablishPath(m); !success {
-			return queue.DecisionSkipped
+		if err := app.pathingHandler.EstablishPath(m); err != nil {
+			return queue.DecisionSkipped, err
 		}
 
-		if success := validation.ValidateMoveable(m); !success {
-			return queue.DecisionSkipped
+		if err := validation.ValidateMoveable(m); err != nil {
+			return queue.DecisionSkipped, err
 		}
 
-		return queue.DecisionSuccess
+		return queue.DecisionSuccess, nil
 	}); err != nil {
 		return fmt.Errorf("(app-eval) %w", err)
 	}
@@ -3,32 +3,42 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 
+	"github.com/desertwitch/gover/internal/progress"
 	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
 )
 
 // IO is the principal method for moving all [schema.Moveable] to their
 // respective target [schema.Storage]. This process happens concurrently,
 // meaning multiple (different) [schema.Storage] get written to at the same
 // time, but with only one I/O write operation ever happening per individual
-// [schema.Storage] (= sequential processing inside one [schema.Storage]).
+// [schema.Storage] (= sequential processing inside one [schema.Storage]),
+// unless a higher worker cap was configured for that [schema.Storage]. Once
+// the queue of a [schema.Storage] has finished, its post hook is run.
 func (app *app) IO(ctx context.Context) error {
 	tasker := queue.NewTaskManager()
 
 	queues := app.queueManager.IOManager.GetQueues()
 
 	for target, targetQueue := range queues {
+		if app.config.Ordering != nil {
+			targetQueue.Reorder(app.config.Ordering)
+		}
+
 		tasker.Add(
-			func(targetQueue *queue.IOTargetQueue) func() {
+			func(target schema.Storage, targetQueue *queue.IOTargetQueue) func() {
 				return func() {
-					_ = app.ioHandler.ProcessTargetQueue(ctx, app.config.Pipelines.IOPipelines, target, targetQueue)
+					if err := app.ioHandler.ProcessTargetQueue(ctx, app.config.Pipelines.IOPipelines, target, targetQueue, app.config.Concurrency.Target(target.GetName())); err != nil {
+						app.reportCollector.AddFailure(progress.StageIO, target.GetName(), err)
+					}
+					app.runTargetPostHook(ctx, target, targetQueue.GetSuccessful(), targetQueue.GetSkipped(), targetQueue.GetBytesTransfered())
 				}
-			}(targetQueue),
+			}(target, targetQueue),
 		)
 	}
 
-	if err := tasker.LaunchConcAndWait(ctx, runtime.NumCPU()); err != nil {
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.IO()); err != nil {
 		return fmt.Errorf("(app-io) %w", err)
 	}
 
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/gover/internal/io"
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// leftoverSourcePresent is the class of a leftover temporary file, of which
+	// the source (of the same share-relative path, and beginning with the same
+	// content) is still present on another storage.
+	leftoverSourcePresent = "source present"
+
+	// leftoverDestExists is the class of a leftover temporary file, of which the
+	// destination (of the same name) exists next to it.
+	leftoverDestExists = "destination exists"
+)
+
+// LaunchSweep starts the application for sweeping all [schema.Storage] for
+// leftover temporary files of the IO (of moves that did not finish). Each is
+// removed if its source is still present (on another [schema.Storage]) and
+// begins with its content, as it is then no longer needed. One with its destination existing next to it is
+// only removed if confirmed, as it may also be a file of the user that merely
+// shares the naming of temporary files. Any other leftover temporary file may
+// be the only copy of a file and is kept (and reported). In a dry-run, all
+// leftover temporary files are only reported.
+func (app *app) LaunchSweep(ctx context.Context, dryRun bool, confirmed bool) error {
+	var found, removed int
+	var removedBytes uint64
+
+	for _, name := range slices.Sorted(maps.Keys(app.storages)) {
+		storage := app.storages[name]
+
+		paths, err := app.fsHandler.FindFilesWithSuffix(ctx, storage.GetFSPath(), io.TempFileSuffix)
+		if err != nil {
+			return fmt.Errorf("(app-sweep) %w", err)
+		}
+
+		for _, path := range paths {
+			found++
+
+			size, ok := app.sweepLeftover(storage, path, dryRun, confirmed)
+			if ok {
+				removed++
+				removedBytes += size
+			}
+		}
+	}
+
+	slog.Info("Sweeping done:",
+		"found", found,
+		"removed", removed,
+		"removedBytes", humanize.IBytes(removedBytes),
+		"dryRun", dryRun,
+	)
+
+	return nil
+}
+
+// sweepLeftover classifies a leftover temporary file on a [schema.Storage] and
+// removes it (unless in a dry-run) if it is no longer needed. It returns its
+// size and whether it was removed. Leftover temporary files that are kept
+// (other than in a dry-run) are recorded as errors of the run.
+func (app *app) sweepLeftover(storage schema.Storage, path string, dryRun bool, confirmed bool) (uint64, bool) {
+	metadata, err := app.fsHandler.GetMetadata(path)
+	if err != nil {
+		slog.Warn("Skipped leftover: failed to stat",
+			"err", err,
+			"path", path,
+		)
+		app.reportCollector.AddError("", "", storage.GetName(), err)
+
+		return 0, false
+	}
+	size := metadata.Size
+
+	class, err := app.classifyLeftover(storage, path, size)
+	if err != nil {
+		slog.Warn("Kept leftover: not safely removable",
+			"err", err,
+			"path", path,
+			"storage", storage.GetName(),
+			"size", humanize.IBytes(size),
+		)
+		app.reportCollector.AddError("", "", storage.GetName(), err)
+
+		return 0, false
+	}
+
+	if dryRun {
+		slog.Info("Leftover (dry-run):",
+			"path", path,
+			"class", class,
+			"storage", storage.GetName(),
+			"size", humanize.IBytes(size),
+		)
+
+		return 0, false
+	}
+
+	if class == leftoverDestExists && !confirmed {
+		err := fmt.Errorf("(app-sweep) %w: %s", ErrUnconfirmedLeftover, path)
+
+		slog.Warn("Kept leftover: review with -dry-run and confirm with -sweep-confirm",
+			"err", err,
+			"path", path,
+			"storage", storage.GetName(),
+			"size", humanize.IBytes(size),
+		)
+		app.reportCollector.AddError("", "", storage.GetName(), err)
+
+		return 0, false
+	}
+
+	if err := app.fsHandler.Remove(path); err != nil {
+		slog.Warn("Failed to remove leftover (skipped)",
+			"err", err,
+			"path", path,
+		)
+		app.reportCollector.AddError("", "", storage.GetName(), err)
+
+		return 0, false
+	}
+
+	slog.Info("Removed leftover:",
+		"path", path,
+		"class", class,
+		"storage", storage.GetName(),
+		"size", humanize.IBytes(size),
+	)
+
+	return size, true
+}
+
+// classifyLeftover returns the class of a leftover temporary file (of the
+// given size) on a [schema.Storage], or an [ErrUnresolvedLeftover] if neither
+// its destination nor its source is present. A source is only considered to
+// be present if it is a regular file that begins with the content of the
+// leftover (as compared by the checksums of the leftover and the same-length
+// prefix of the source), so that the leftover is a partial copy of it.
+func (app *app) classifyLeftover(storage schema.Storage, path string, size uint64) (string, error) {
+	destPath := strings.TrimSuffix(path, io.TempFileSuffix)
+
+	if metadata, err := app.pathMetadata(destPath); err != nil {
+		return "", err
+	} else if metadata != nil {
+		return leftoverDestExists, nil
+	}
+
+	rel, err := filepath.Rel(storage.GetFSPath(), destPath)
+	if err != nil {
+		return "", fmt.Errorf("(app-sweep) failed to get relative path: %w", err)
+	}
+
+	var checksum string
+
+	for _, other := range app.storages {
+		if other.GetName() == storage.GetName() {
+			continue
+		}
+
+		srcPath := filepath.Join(other.GetFSPath(), rel)
+
+		metadata, err := app.pathMetadata(srcPath)
+		if err != nil {
+			return "", err
+		}
+
+		if metadata == nil || metadata.IsDir || metadata.IsSymlink || metadata.Size < size {
+			continue
+		}
+
+		if checksum == "" {
+			if checksum, err = app.fsHandler.GetChecksum(path); err != nil {
+				return "", fmt.Errorf("(app-sweep) %w", err)
+			}
+		}
+
+		prefixChecksum, err := app.fsHandler.GetPrefixChecksum(srcPath, size)
+		if err != nil {
+			return "", fmt.Errorf("(app-sweep) %w", err)
+		}
+
+		if prefixChecksum == checksum {
+			return leftoverSourcePresent, nil
+		}
+	}
+
+	return "", fmt.Errorf("(app-sweep) %w: %s", ErrUnresolvedLeftover, path)
+}
+
+// pathMetadata returns the [schema.Metadata] of a path (without following
+// symlinks), or nil if it does not exist.
+func (app *app) pathMetadata(path string) (*schema.Metadata, error) {
+	metadata, err := app.fsHandler.GetMetadata(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("(app-stat) %w", err)
+	}
+
+	return metadata, nil
+}
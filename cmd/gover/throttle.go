@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/throttle"
+)
+
+// watchBandwidthReload reloads the bandwidth limits of the [throttle.Limiter]
+// (with the configuration file being re-read) on every SIGHUP, until the
+// context is cancelled. The returned function stops the watching again.
+func (app *app) watchBandwidthReload(ctx context.Context) func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			select {
+			case <-watchCtx.Done():
+				return
+			case <-sigChan:
+				app.reloadBandwidth()
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		cancel()
+		<-done
+	}
+}
+
+// reloadBandwidth replaces the bandwidth limits of the [throttle.Limiter] with
+// those of the layered options (see [readBandwidthLimits]), keeping them if
+// these cannot be read.
+func (app *app) reloadBandwidth() {
+	limits, err := readBandwidthLimits(configFilePath())
+	if err != nil {
+		slog.Warn("Failed to reload the bandwidth limits (kept)",
+			"err", err,
+		)
+
+		return
+	}
+
+	app.limiter.SetLimits(limits)
+
+	slog.Info("Bandwidth limits reloaded:",
+		"global", limits.Global,
+		"sources", limits.Sources,
+		"targets", limits.Targets,
+	)
+}
+
+// readBandwidthLimits reads the [throttle.Limits] from the same layers as the
+// application's options (see [configuration.Handler.ApplyLayers]), with the
+// configuration file at the given path being re-read: the configuration file
+// (with missing keys meaning no limit), the environment and the explicitly set
+// command-line flags, in ascending order of precedence.
+func readBandwidthLimits(path string) (throttle.Limits, error) {
+	global := &rateValue{}
+	sources := &ratesValue{rates: make(map[string]uint64)}
+	targets := &ratesValue{rates: make(map[string]uint64)}
+
+	flags := flag.NewFlagSet("bandwidth", flag.ContinueOnError)
+	flags.Var(global, "bwlimit", "")
+	flags.Var(sources, "source-bwlimit", "")
+	flags.Var(targets, "target-bwlimit", "")
+
+	var ignored []string
+	flag.VisitAll(func(f *flag.Flag) {
+		if flags.Lookup(f.Name) == nil {
+			ignored = append(ignored, f.Name)
+		}
+	})
+
+	var err error
+	flag.Visit(func(f *flag.Flag) {
+		if flags.Lookup(f.Name) != nil && err == nil {
+			err = flags.Set(f.Name, f.Value.String())
+		}
+	})
+	if err != nil {
+		return throttle.Limits{}, fmt.Errorf("(main-throttle) %w", err)
+	}
+
+	if err := configuration.NewHandler(&configuration.GodotenvProvider{}).ApplyLayers(flags, path, os.Environ(), ignored...); err != nil {
+		return throttle.Limits{}, fmt.Errorf("(main-throttle) %w", err)
+	}
+
+	return throttle.Limits{
+		Global:  global.rate,
+		Sources: sources.rates,
+		Targets: targets.rates,
+	}, nil
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/gover/internal/journal"
+	"github.com/desertwitch/gover/internal/pathing"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/desertwitch/gover/internal/validation"
+)
+
+// undoRun holds all reversed moves of a run that is to be undone.
+type undoRun struct {
+	// The reversed "parent" [schema.Moveable], in order of the journal.
+	moveables []*schema.Moveable
+
+	// The [journal.Entry] of every reversed [schema.Moveable] (including any
+	// subelements).
+	entries map[*schema.Moveable]*journal.Entry
+
+	// The recorded (original) metadata of directories, by their original path.
+	dirs map[string]*journal.Metadata
+}
+
+// LaunchUndo starts the application for undoing a previous run (of the given
+// identifier), as it was recorded in the journal at the given path:
+//   - Reversal of all moves of the run and verification of every reversed
+//     [schema.Moveable] against any change since the run.
+//   - IO to move all [schema.Moveable] back to their recorded sources.
+func (app *app) LaunchUndo(ctx context.Context, path string, runID string) error {
+	if path == "" {
+		return fmt.Errorf("(app) %w: undo requires -journal", ErrNoJournal)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("(app) failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := journal.Read(f, runID)
+	if err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	slog.Info("Undoing run:",
+		"runId", runID,
+		"path", path,
+		"entries", len(entries),
+	)
+
+	if err := app.Undo(ctx, entries); err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	if err := app.IO(ctx); err != nil {
+		return fmt.Errorf("(app) %w", err)
+	}
+
+	return nil
+}
+
+// Undo is the principal method for reversing all moves of the [journal.Entry]
+// of a run, verifying them and enqueueing them into the [queue.IOManager]. It
+// takes the place of both enumeration and evaluation, so that exactly the moves
+// of the run are reversed (minus those that have changed since). This process
+// happens concurrently, meaning that multiple [schema.Share]'s are verified at
+// the same time.
+func (app *app) Undo(ctx context.Context, entries []*journal.Entry) error {
+	tasker := queue.NewTaskManager()
+
+	u := app.reverseRun(entries)
+	app.queueManager.EvaluationManager.Enqueue(u.moveables...)
+
+	for share, shareQueue := range app.queueManager.EvaluationManager.GetQueues() {
+		tasker.Add(
+			func(share schema.Share, shareQueue *queue.EvaluationShareQueue) func() {
+				return func() {
+					if err := app.verifyUndoToIO(ctx, shareQueue, u); err != nil {
+						slog.Warn("Skipped verifying share due to failure:",
+							"err", err,
+							"share", share.GetName(),
+						)
+						app.reportCollector.AddFailure(progress.StageEvaluation, share.GetName(), err)
+					}
+				}
+			}(share, shareQueue),
+		)
+	}
+
+	if err := tasker.LaunchConcAndWait(ctx, app.config.Concurrency.Evaluation()); err != nil {
+		return fmt.Errorf("(app-undo) %w", err)
+	}
+
+	return nil
+}
+
+// reverseRun reverses all moves of the [journal.Entry] of a run into
+// [schema.Moveable], from their recorded destinations back to their recorded
+// sources. Hardlinks and symlinks to files of the run become subelements of
+// these, while all other symlinks are reversed on their own.
+func (app *app) reverseRun(entries []*journal.Entry) *undoRun {
+	u := &undoRun{
+		entries: make(map[*schema.Moveable]*journal.Entry),
+		dirs:    make(map[string]*journal.Metadata),
+	}
+
+	files := make(map[string]*schema.Moveable)
+	links := []*journal.Entry{}
+
+	for _, e := range entries {
+		switch {
+		case e.Op == journal.OpCreate || e.Op == journal.OpRemove:
+			if e.Kind == journal.KindDirectory && e.Metadata != nil {
+				u.dirs[e.SourcePath] = e.Metadata
+			}
+
+		case e.Op != journal.OpMove:
+			continue
+
+		case e.Kind == journal.KindHardlink || e.Kind == journal.KindSymlink:
+			links = append(links, e)
+
+		default:
+			if m := app.reverseEntry(e); m != nil {
+				u.moveables = append(u.moveables, m)
+				u.entries[m] = e
+
+				if e.Kind == journal.KindFile {
+					files[e.DestPath] = m
+				}
+			}
+		}
+	}
+
+	for _, e := range links {
+		parent, internalLink := files[e.LinkTo]
+
+		if !internalLink && e.Kind == journal.KindHardlink {
+			slog.Warn("Skipped job: hardlink target not undone",
+				"err", ErrLinkTargetMissing,
+				"path", e.LinkTo,
+				"job", e.DestPath,
+				"share", e.Share,
+			)
+			app.reportCollector.AddError(progress.StageEvaluation, e.Share, "", ErrLinkTargetMissing)
+
+			continue
+		}
+
+		m := app.reverseEntry(e)
+		if m == nil {
+			continue
+		}
+		u.entries[m] = e
+
+		switch {
+		case !internalLink:
+			u.moveables = append(u.moveables, m)
+
+		case e.Kind == journal.KindHardlink:
+			m.IsHardlink = true
+			m.HardlinkTo = parent
+			parent.Hardlinks = append(parent.Hardlinks, m)
+
+		default:
+			m.IsSymlink = true
+			m.SymlinkTo = parent
+			parent.Symlinks = append(parent.Symlinks, m)
+		}
+	}
+
+	return u
+}
+
+// reverseEntry returns a pointer to a new [schema.Moveable] reversing the move
+// of a [journal.Entry], or nil if its [schema.Share] or [schema.Storage] no
+// longer exist.
+func (app *app) reverseEntry(e *journal.Entry) *schema.Moveable {
+	share, shareExists := app.shares[e.Share]
+	source, sourceExists := app.storages[e.Dest]
+	dest, destExists := app.storages[e.Source]
+
+	if !shareExists || !sourceExists || !destExists {
+		slog.Warn("Skipped job: share or storage of the run no longer exists",
+			"src", e.Dest,
+			"dst", e.Source,
+			"job", e.DestPath,
+			"share", e.Share,
+		)
+
+		return nil
+	}
+
+	metadata := e.Metadata.ToSchema()
+	if metadata == nil {
+		// No metadata gets refused as changed when verifying.
+		metadata = &schema.Metadata{}
+	}
+	metadata.Size = e.Size
+	metadata.IsDir = e.Kind == journal.KindDirectory
+
+	if e.Kind == journal.KindSymlink {
+		metadata.IsSymlink = true
+		metadata.SymlinkTo = e.LinkTo
+	}
+
+	return &schema.Moveable{
+		Share:      share,
+		Source:     source,
+		SourcePath: e.DestPath,
+		Dest:       dest,
+		DestPath:   e.SourcePath,
+		Metadata:   metadata,
+	}
+}
+
+// verifyUndoToIO is the processing logic for an [queue.EvaluationShareQueue]
+// that was filled from the reversed moves of a run. Every [schema.Moveable] is
+// re-checked against its [journal.Entry] and its original path, enqueueing
+// those that have not changed since the run into the [queue.IOManager].
+func (app *app) verifyUndoToIO(ctx context.Context, q *queue.EvaluationShareQueue, u *undoRun) error {
+	if err := q.DequeueAndProcessConc(ctx, app.config.Concurrency.Share(), func(m *schema.Moveable) (int, error) {
+		if err := app.verifyUndo(m, u); err != nil {
+			slog.Warn("Skipped job: cannot be undone",
+				"err", err,
+				"job", m.SourcePath,
+				"share", m.Share.GetName(),
+			)
+
+			return queue.DecisionSkipped, err
+		}
+
+		for _, subelem := range slices.Concat(m.Hardlinks, m.Symlinks) {
+			if err := app.verifyUndo(subelem, u); err != nil {
+				slog.Warn("Skipped job: subjob cannot be undone",
+					"err", err,
+					"subjob", subelem.SourcePath,
+					"job", m.SourcePath,
+					"share", m.Share.GetName(),
+				)
+
+				return queue.DecisionSkipped, err
+			}
+
+			if subelem.IsHardlink && subelem.Metadata.Inode != m.Metadata.Inode {
+				err := fmt.Errorf("(app-undo) %w: no longer hardlinked", journal.ErrDestChanged)
+
+				slog.Warn("Skipped job: subjob cannot be undone",
+					"err", err,
+					"subjob", subelem.SourcePath,
+					"job", m.SourcePath,
+					"share", m.Share.GetName(),
+				)
+
+				return queue.DecisionSkipped, err
+			}
+		}
+
+		if err := validation.ValidateMoveable(m); err != nil {
+			return queue.DecisionSkipped, err
+		}
+
+		return queue.DecisionSuccess, nil
+	}); err != nil {
+		return fmt.Errorf("(app-undo) %w", err)
+	}
+
+	app.queueManager.IOManager.Enqueue(q.GetSuccessful()...)
+
+	return nil
+}
+
+// verifyUndo checks a reversed [schema.Moveable] for any change of its current
+// path (the destination of the run) since the run, including the checksum of a
+// file, and checks that its original path is not taken. Once verified, its
+// directory structure is established for recreation.
+func (app *app) verifyUndo(m *schema.Moveable, u *undoRun) error {
+	e := u.entries[m]
+
+	current, err := app.fsHandler.GetMetadata(m.SourcePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("(app-undo) %w: vanished", journal.ErrDestChanged)
+		}
+
+		return fmt.Errorf("(app-undo) %w", err)
+	}
+
+	if err := journal.CheckChanged(e, current); err != nil {
+		return fmt.Errorf("(app-undo) %w", err)
+	}
+	m.Metadata.Inode = current.Inode
+
+	if e.Kind == journal.KindFile && e.Checksum != "" {
+		checksum, err := app.fsHandler.GetChecksum(m.SourcePath)
+		if err != nil {
+			return fmt.Errorf("(app-undo) %w", err)
+		}
+
+		if checksum != e.Checksum {
+			return fmt.Errorf("(app-undo) %w: checksum %s != %s", journal.ErrDestChanged, checksum, e.Checksum)
+		}
+	}
+
+	// A directory is allowed to exist, that gets handled later in IO.
+	if !m.Metadata.IsDir {
+		existing, err := app.pathMetadata(m.DestPath)
+		if err != nil {
+			return fmt.Errorf("(app-undo) %w", err)
+		}
+
+		if existing != nil {
+			return fmt.Errorf("(app-undo) %w: %s", pathing.ErrPathExistsOnDest, m.DestPath)
+		}
+	}
+
+	if err := app.establishUndoDirs(m, u); err != nil {
+		return fmt.Errorf("(app-undo) %w", err)
+	}
+
+	return nil
+}
+
+// establishUndoDirs establishes the directory structure of a reversed
+// [schema.Moveable], from its share directory down to its parent directory, for
+// recreation at the original paths. Directories are recreated with their
+// recorded (original) metadata, or otherwise with their current metadata.
+func (app *app) establishUndoDirs(m *schema.Moveable, u *undoRun) error {
+	var prevElement *schema.Directory
+
+	path := filepath.Clean(m.SourcePath)
+	basePath := filepath.Join(m.Source.GetFSPath(), m.Share.GetName())
+
+	for {
+		parentPath := filepath.Dir(path)
+
+		if parentPath == path || !strings.HasPrefix(parentPath, basePath) {
+			break
+		}
+
+		path = parentPath
+
+		relPath, err := filepath.Rel(m.Source.GetFSPath(), path)
+		if err != nil {
+			return fmt.Errorf("(app-undodirs) failed to rel: %w", err)
+		}
+
+		thisElement := &schema.Directory{
+			SourcePath: path,
+			DestPath:   filepath.Join(m.Dest.GetFSPath(), relPath),
+		}
+
+		if recorded, ok := u.dirs[thisElement.DestPath]; ok {
+			thisElement.Metadata = recorded.ToSchema()
+			thisElement.Metadata.IsDir = true
+		} else {
+			metadata, err := app.fsHandler.GetMetadata(path)
+			if err != nil {
+				return fmt.Errorf("(app-undodirs) %w", err)
+			}
+			thisElement.Metadata = metadata
+		}
+
+		if prevElement != nil {
+			thisElement.Child = prevElement
+			prevElement.Parent = thisElement
+		}
+		prevElement = thisElement
+	}
+
+	m.RootDir = prevElement
+
+	return nil
+}
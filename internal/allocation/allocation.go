@@ -61,12 +61,14 @@ func NewHandler(fsHandler fsProvider) *Handler {
 }
 
 // AllocateArrayDestination allocates a [schema.Moveable] and its subelements to
-// a [schema.Share]'s included disks (which are typically part of an array).
+// a [schema.Share]'s included disks (which are typically part of an array),
+// returning the error of the first element (or subelement) that could not be
+// allocated.
 //
 // It is the principal method used allocating given [schema.Moveable], where the
 // destination field has not yet been set, to target [schema.Disk] (of an
 // array).
-func (a *Handler) AllocateArrayDestination(m *schema.Moveable) bool {
+func (a *Handler) AllocateArrayDestination(m *schema.Moveable) error {
 	dest, err := a.allocateArrayDestination(m)
 	if err != nil {
 		slog.Warn("Skipped job: failed to allocate array destination",
@@ -75,7 +77,7 @@ func (a *Handler) AllocateArrayDestination(m *schema.Moveable) bool {
 			"share", m.Share.GetName(),
 		)
 
-		return false
+		return err
 	}
 	m.Dest = dest
 
@@ -83,7 +85,6 @@ func (a *Handler) AllocateArrayDestination(m *schema.Moveable) bool {
 		h.Dest = dest
 	}
 
-	symlinkFailure := false
 	for _, s := range m.Symlinks {
 		dest, err := a.allocateArrayDestination(s)
 		if err != nil {
@@ -94,17 +95,13 @@ func (a *Handler) AllocateArrayDestination(m *schema.Moveable) bool {
 				"job", m.SourcePath,
 				"share", s.Share.GetName(),
 			)
-			symlinkFailure = true
 
-			break
+			return err
 		}
 		s.Dest = dest
 	}
-	if symlinkFailure {
-		return false
-	}
 
-	return true
+	return nil
 }
 
 // allocateArrayDestination provides the allocation logic for allocating a
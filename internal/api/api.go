@@ -0,0 +1,302 @@
+// Package api implements an opt-in local HTTP API for observing and controlling
+// the application. It serves the [queue.Progress] of all managers and queues,
+// the most recent log records, control endpoints (cancel, pause and resume),
+// the bandwidth limits (which can also be changed), as well as a WebSocket
+// channel pushing updates.
+//
+// The [Handler] is a regular [http.Handler], so it can also be used in-process
+// (e.g. with [net/http/httptest]) against any [queue.Manager].
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/throttle"
+)
+
+const (
+	// readHeaderTimeout is the timeout for reading a request's headers.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout is the timeout for gracefully shutting down the server.
+	shutdownTimeout = 5 * time.Second
+
+	// unixPrefix is the address prefix denoting a Unix socket path.
+	unixPrefix = "unix:"
+
+	// maxBodySize is the maximum size of a request's body.
+	maxBodySize = 1 << 20
+)
+
+// State is the machine-readable control state of the application.
+type State struct {
+	Paused    bool `json:"paused"`
+	Cancelled bool `json:"cancelled"`
+}
+
+// Update is the message pushed via the WebSocket channel, containing the most
+// recent [progress.Snapshot] and all [LogRecord] since the previous update.
+type Update struct {
+	State    State              `json:"state"`
+	Progress *progress.Snapshot `json:"progress"`
+	Logs     []LogRecord        `json:"logs"`
+}
+
+// Handler is the principal implementation of the API services.
+type Handler struct {
+	ctx          context.Context //nolint:containedctx
+	cancel       context.CancelFunc
+	queueManager *queue.Manager
+	limiter      *throttle.Limiter
+	logBuffer    *LogBuffer
+	interval     time.Duration
+	mux          *http.ServeMux
+	connsMutex   sync.Mutex
+	conns        map[net.Conn]struct{}
+}
+
+// NewHandler returns a pointer to a new API [Handler]. The given context and
+// its cancel function are those of the application, with the latter being used
+// for the cancel endpoint. The [throttle.Limiter] is that of the IO, for the
+// bandwidth limit endpoints. The interval is that of WebSocket updates.
+func NewHandler(ctx context.Context, cancel context.CancelFunc, queueManager *queue.Manager, limiter *throttle.Limiter, logBuffer *LogBuffer, interval time.Duration) *Handler {
+	h := &Handler{
+		ctx:          ctx,
+		cancel:       cancel,
+		queueManager: queueManager,
+		limiter:      limiter,
+		logBuffer:    logBuffer,
+		interval:     interval,
+		mux:          http.NewServeMux(),
+		conns:        make(map[net.Conn]struct{}),
+	}
+
+	h.mux.HandleFunc("GET /api/v1/progress", h.handleProgress)
+	h.mux.HandleFunc("GET /api/v1/logs", h.handleLogs)
+	h.mux.HandleFunc("GET /api/v1/state", h.handleState)
+	h.mux.HandleFunc("POST /api/v1/cancel", h.handleCancel)
+	h.mux.HandleFunc("POST /api/v1/pause", h.handlePause)
+	h.mux.HandleFunc("POST /api/v1/resume", h.handleResume)
+	h.mux.HandleFunc("GET /api/v1/throttle", h.handleThrottle)
+	h.mux.HandleFunc("PUT /api/v1/throttle", h.handleSetThrottle)
+	h.mux.HandleFunc("GET /api/v1/ws", h.handleWebSocket)
+
+	return h
+}
+
+// ServeHTTP serves a HTTP request, making the [Handler] a [http.Handler].
+// Requests that cannot have originated from a local client are rejected, as
+// are state-changing requests that are not of the JSON content type.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, err := checkRequest(r); err != nil {
+		writeError(w, status, err)
+
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// Serve serves the API on a given [net.Listener] until the context is
+// cancelled, at which point the server is gracefully shut down and all
+// remaining WebSocket connections are closed.
+func (h *Handler) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx) //nolint:contextcheck
+		h.closeConns()
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("(api) failed to serve: %w", err)
+	}
+
+	return nil
+}
+
+// Listen returns a [net.Listener] for an address, which is either a Unix
+// socket path (prefixed with "unix:") or a TCP address on a loopback
+// interface (e.g. "localhost:8080" or "127.0.0.1:8080").
+func Listen(address string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, unixPrefix); ok {
+		if err := removeStaleSocket(path); err != nil {
+			return nil, err
+		}
+
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("(api) failed to listen: %w", err)
+		}
+
+		return listener, nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("(api) failed to parse address: %w", err)
+	}
+
+	if !isLoopbackHost(host) {
+		return nil, fmt.Errorf("(api) %w: %s", ErrNotLocal, address)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("(api) failed to listen: %w", err)
+	}
+
+	return listener, nil
+}
+
+// removeStaleSocket removes a Unix socket left behind by a previous process,
+// but only if no other process is still accepting connections on it.
+func removeStaleSocket(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return nil //nolint:nilerr
+	}
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+
+		return fmt.Errorf("(api) %w: %s", ErrSocketInUse, path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("(api) failed to remove stale socket: %w", err)
+	}
+
+	return nil
+}
+
+// handleProgress serves the [progress.Snapshot] of the [queue.Manager].
+func (h *Handler) handleProgress(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, progress.Collect(h.queueManager))
+}
+
+// handleLogs serves the most recent [LogRecord], limited by the optional
+// "limit" query parameter, or those after the optional "since" parameter.
+func (h *Handler) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if since := r.URL.Query().Get("since"); since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid since: %w", err))
+
+			return
+		}
+
+		records, _ := h.logBuffer.Since(seq)
+		writeJSON(w, http.StatusOK, records)
+
+		return
+	}
+
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %w", err))
+
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, h.logBuffer.Recent(limit))
+}
+
+// handleState serves the current [State].
+func (h *Handler) handleState(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.state())
+}
+
+// handleCancel cancels the application and serves the resulting [State].
+func (h *Handler) handleCancel(w http.ResponseWriter, _ *http.Request) {
+	slog.Warn("Cancellation requested via API.")
+	h.cancel()
+
+	writeJSON(w, http.StatusOK, h.state())
+}
+
+// handlePause pauses the [queue.Manager] and serves the resulting [State].
+func (h *Handler) handlePause(w http.ResponseWriter, _ *http.Request) {
+	slog.Info("Pause requested via API.")
+	h.queueManager.Pause()
+
+	writeJSON(w, http.StatusOK, h.state())
+}
+
+// handleResume resumes the [queue.Manager] and serves the resulting [State].
+func (h *Handler) handleResume(w http.ResponseWriter, _ *http.Request) {
+	slog.Info("Resume requested via API.")
+	h.queueManager.Resume()
+
+	writeJSON(w, http.StatusOK, h.state())
+}
+
+// handleThrottle serves the current [throttle.Limits].
+func (h *Handler) handleThrottle(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.limiter.Limits())
+}
+
+// handleSetThrottle replaces the [throttle.Limits] with those of the request
+// (in bytes per second) and serves the resulting [throttle.Limits].
+func (h *Handler) handleSetThrottle(w http.ResponseWriter, r *http.Request) {
+	var limits throttle.Limits
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&limits); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limits: %w", err))
+
+		return
+	}
+
+	slog.Info("Bandwidth limits changed via API.",
+		"global", limits.Global,
+		"sources", limits.Sources,
+		"targets", limits.Targets,
+	)
+	h.limiter.SetLimits(limits)
+
+	writeJSON(w, http.StatusOK, h.limiter.Limits())
+}
+
+// state returns the current [State].
+func (h *Handler) state() State {
+	return State{
+		Paused:    h.queueManager.IsPaused(),
+		Cancelled: h.ctx.Err() != nil,
+	}
+}
+
+// writeJSON writes a value as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error as JSON response with the given status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
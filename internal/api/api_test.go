@@ -0,0 +1,390 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/throttle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) (*Handler, context.CancelFunc, *LogBuffer) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	logBuffer := NewLogBuffer(10, slog.LevelInfo) //nolint:mnd
+	limiter := throttle.NewLimiter(throttle.Limits{Global: 100})
+
+	return NewHandler(ctx, cancel, queue.NewManager(), limiter, logBuffer, 10*time.Millisecond), cancel, logBuffer
+}
+
+func serve(t *testing.T, h http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Host = "localhost"
+
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&v))
+
+	return v
+}
+
+func TestHandleProgress(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	rec := serve(t, h, http.MethodGet, "/api/v1/progress", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	snapshot := decode[progress.Snapshot](t, rec)
+	assert.False(t, snapshot.Time.IsZero())
+}
+
+func TestHandleLogs(t *testing.T) {
+	h, _, logBuffer := newTestHandler(t)
+
+	logger := slog.New(logBuffer)
+	logger.Info("first", "key", "value")
+	logger.Info("second")
+	logger.Debug("dropped")
+	logger.Warn("third")
+
+	tests := []struct {
+		name     string
+		target   string
+		status   int
+		messages []string
+	}{
+		{"all", "/api/v1/logs", http.StatusOK, []string{"first", "second", "third"}},
+		{"limit", "/api/v1/logs?limit=2", http.StatusOK, []string{"second", "third"}},
+		{"since", "/api/v1/logs?since=1", http.StatusOK, []string{"second", "third"}},
+		{"invalid limit", "/api/v1/logs?limit=x", http.StatusBadRequest, nil},
+		{"invalid since", "/api/v1/logs?since=-1", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, http.MethodGet, tt.target, "")
+			require.Equal(t, tt.status, rec.Code)
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var messages []string
+			for _, record := range decode[[]LogRecord](t, rec) {
+				messages = append(messages, record.Message)
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestHandleControl(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	rec := serve(t, h, http.MethodGet, "/api/v1/state", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, State{}, decode[State](t, rec))
+
+	rec = serve(t, h, http.MethodPost, "/api/v1/pause", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, State{Paused: true}, decode[State](t, rec))
+	assert.True(t, h.queueManager.IsPaused())
+
+	rec = serve(t, h, http.MethodPost, "/api/v1/resume", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, State{}, decode[State](t, rec))
+	assert.False(t, h.queueManager.IsPaused())
+
+	rec = serve(t, h, http.MethodPost, "/api/v1/cancel", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, State{Cancelled: true}, decode[State](t, rec))
+	require.Error(t, h.ctx.Err())
+
+	rec = serve(t, h, http.MethodGet, "/api/v1/cancel", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandleThrottle(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	rec := serve(t, h, http.MethodGet, "/api/v1/throttle", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint64(100), decode[throttle.Limits](t, rec).Global)
+
+	rec = serve(t, h, http.MethodPut, "/api/v1/throttle", `{"global":0,"targets":{"disk1":2048}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	limits := decode[throttle.Limits](t, rec)
+	assert.Equal(t, uint64(0), limits.Global)
+	assert.Equal(t, map[string]uint64{"disk1": 2048}, limits.Targets)
+	assert.Equal(t, limits, h.limiter.Limits())
+
+	rec = serve(t, h, http.MethodPut, "/api/v1/throttle", `{"global":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, limits, h.limiter.Limits())
+}
+
+func TestCheckRequest(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		host        string
+		origin      string
+		contentType string
+		status      int
+	}{
+		{"local host", http.MethodGet, "localhost", "", "", http.StatusOK},
+		{"loopback address", http.MethodGet, "127.0.0.1:8080", "", "", http.StatusOK},
+		{"loopback ipv6 address", http.MethodGet, "[::1]:8080", "", "", http.StatusOK},
+		{"foreign host", http.MethodGet, "evil.example:8080", "", "", http.StatusForbidden},
+		{"same origin", http.MethodGet, "localhost:8080", "http://localhost:8080", "", http.StatusOK},
+		{"foreign origin", http.MethodGet, "localhost:8080", "http://evil.example", "", http.StatusForbidden},
+		{"null origin", http.MethodGet, "localhost:8080", "null", "", http.StatusForbidden},
+		{"json post", http.MethodPost, "localhost", "", "application/json; charset=utf-8", http.StatusOK},
+		{"simple post", http.MethodPost, "localhost", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"post without content type", http.MethodPost, "localhost", "", "", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/api/v1/state"
+			if tt.method == http.MethodPost {
+				target = "/api/v1/pause"
+			}
+
+			req := httptest.NewRequest(tt.method, target, nil)
+			req.Host = tt.host
+
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+
+			if tt.method == http.MethodPost {
+				assert.Equal(t, tt.status == http.StatusOK, h.queueManager.IsPaused())
+				h.queueManager.Resume()
+			}
+		})
+	}
+}
+
+func TestCheckRequestPort(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/api/v1/state", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req.Host = "localhost:1"
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleWebSocketBadHandshake(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	rec := serve(t, h, http.MethodGet, "/api/v1/ws", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleWebSocket(t *testing.T) {
+	h, cancel, logBuffer := newTestHandler(t)
+
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	conn, r := dialWebSocket(t, server.Listener.Addr().String())
+
+	opcode, payload := readServerFrame(t, r)
+	require.Equal(t, byte(wsOpText), opcode)
+
+	var update Update
+	require.NoError(t, json.Unmarshal(payload, &update))
+	assert.NotNil(t, update.Progress)
+
+	slog.New(logBuffer).Info("pushed")
+
+	require.Eventually(t, func() bool {
+		opcode, payload := readServerFrame(t, r)
+		require.Equal(t, byte(wsOpText), opcode)
+		require.NoError(t, json.Unmarshal(payload, &update))
+
+		return len(update.Logs) == 1 && update.Logs[0].Message == "pushed"
+	}, time.Second, time.Millisecond)
+
+	writeClientFrame(t, conn, wsOpPing, []byte("ping"))
+
+	require.Eventually(t, func() bool {
+		opcode, payload := readServerFrame(t, r)
+
+		return opcode == wsOpPong && string(payload) == "ping"
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		opcode, _ := readServerFrame(t, r)
+		if opcode == wsOpText {
+			return false
+		}
+		require.Equal(t, byte(wsOpClose), opcode)
+
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func TestServeClosesWebSockets(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- h.Serve(ctx, listener)
+	}()
+
+	conn, r := dialWebSocket(t, listener.Addr().String())
+	readServerFrame(t, r)
+
+	cancel()
+
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server was not shut down")
+	}
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	for {
+		if _, err := r.ReadByte(); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+
+			break
+		}
+	}
+}
+
+// dialWebSocket opens a WebSocket connection to the handler at an address.
+func dialWebSocket(t *testing.T, address string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = io.WriteString(conn, "GET /api/v1/ws HTTP/1.1\r\nHost: "+address+"\r\n"+
+		"Connection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	require.NoError(t, err)
+
+	r := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(r, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, r
+}
+
+// readServerFrame reads a single (unmasked) server frame.
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(r, header[:])
+	require.NoError(t, err)
+
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, err := io.ReadFull(r, ext[:])
+		require.NoError(t, err)
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+
+	case 127:
+		var ext [8]byte
+		_, err := io.ReadFull(r, ext[:])
+		require.NoError(t, err)
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	require.NoError(t, err)
+
+	return header[0] & 0x0F, payload
+}
+
+// writeClientFrame writes a single (final and masked) client frame.
+func writeClientFrame(t *testing.T, w io.Writer, opcode byte, payload []byte) {
+	t.Helper()
+
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := w.Write(frame)
+	require.NoError(t, err)
+}
@@ -0,0 +1,31 @@
+package api
+
+import "errors"
+
+var (
+	// ErrNotLocal occurs when the API is requested to listen on an address
+	// that is neither a Unix socket nor a loopback address.
+	ErrNotLocal = errors.New("address is not local")
+
+	// ErrSocketInUse occurs when the API is requested to listen on a Unix
+	// socket that is already in use by another process.
+	ErrSocketInUse = errors.New("socket is in use")
+
+	// ErrForeignHost occurs when a request is for a host that is not the
+	// loopback address being listened on.
+	ErrForeignHost = errors.New("host is not local")
+
+	// ErrForeignOrigin occurs when a request originates from a website other
+	// than the API itself (a cross-site request).
+	ErrForeignOrigin = errors.New("origin is not local")
+
+	// ErrContentType occurs when a state-changing request is not of the JSON
+	// content type.
+	ErrContentType = errors.New("content type is not application/json")
+
+	// ErrBadHandshake occurs when a WebSocket handshake is invalid.
+	ErrBadHandshake = errors.New("bad websocket handshake")
+
+	// ErrBadFrame occurs when a WebSocket frame is invalid.
+	ErrBadFrame = errors.New("bad websocket frame")
+)
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// jsonContentType is the content type required for state-changing requests.
+	jsonContentType = "application/json"
+
+	// defaultHTTPPort is the port of a HTTP request's host without a port.
+	defaultHTTPPort = 80
+)
+
+// checkRequest checks that a request can only have originated from a local
+// client, returning the status code to reject it with otherwise. A browser on
+// the same machine could else be made to reach the API by a foreign website,
+// with cross-site requests or by re-binding a foreign name to the loopback
+// address.
+func checkRequest(r *http.Request) (int, error) {
+	if err := checkHost(r); err != nil {
+		return http.StatusForbidden, err
+	}
+
+	if err := checkOrigin(r); err != nil {
+		return http.StatusForbidden, err
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if err := checkContentType(r); err != nil {
+			return http.StatusUnsupportedMediaType, err
+		}
+	}
+
+	return 0, nil
+}
+
+// checkHost checks that the host of a request is a loopback address with the
+// port being listened on. Requests on a Unix socket are not checked, as these
+// cannot be made by a browser.
+func checkHost(r *http.Request) error {
+	local, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if local != nil && local.Network() == "unix" {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, port = r.Host, strconv.Itoa(defaultHTTPPort)
+	}
+
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("(api) %w: %s", ErrForeignHost, r.Host)
+	}
+
+	if tcp, ok := local.(*net.TCPAddr); ok && port != strconv.Itoa(tcp.Port) {
+		return fmt.Errorf("(api) %w: %s", ErrForeignHost, r.Host)
+	}
+
+	return nil
+}
+
+// checkOrigin checks that the origin of a request, if any was sent (as is by
+// browsers for cross-site and WebSocket requests), is the host of the request.
+func checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+		return fmt.Errorf("(api) %w: %s", ErrForeignOrigin, origin)
+	}
+
+	return nil
+}
+
+// checkContentType checks that the content type of a request is JSON, which
+// a browser cannot send cross-site without the API's permission (preflight).
+func checkContentType(r *http.Request) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != jsonContentType {
+		return fmt.Errorf("(api) %w: %q", ErrContentType, r.Header.Get("Content-Type"))
+	}
+
+	return nil
+}
+
+// isLoopbackHost returns if a host is "localhost" or a loopback address.
+func isLoopbackHost(host string) bool {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogRecord is the machine-readable representation of a [slog.Record], as
+// held by a [LogBuffer].
+type LogRecord struct {
+	Seq     uint64         `json:"seq"`
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// logRing is the ring buffer of [LogRecord] shared by a [LogBuffer] and all
+// of its derived [LogBuffer] (with attributes or groups).
+type logRing struct {
+	sync.RWMutex
+
+	records []LogRecord
+	size    int
+	lastSeq uint64
+}
+
+// LogBuffer is an implementation of a [slog.Handler] that keeps a limited
+// amount of the most recent log records in memory, so they can be served.
+type LogBuffer struct {
+	ring   *logRing
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewLogBuffer returns a pointer to a new [LogBuffer], holding at most size
+// of the most recent log records at or above the given level.
+func NewLogBuffer(size int, level slog.Leveler) *LogBuffer {
+	return &LogBuffer{
+		ring: &logRing{
+			records: make([]LogRecord, 0, size),
+			size:    size,
+		},
+		level: level,
+	}
+}
+
+// Enabled returns whether a log record of the given level is to be held.
+func (b *LogBuffer) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= b.level.Level()
+}
+
+// Handle converts a [slog.Record] into a [LogRecord] and adds it to the ring
+// buffer, evicting the oldest held [LogRecord] if the buffer is full.
+func (b *LogBuffer) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]any, len(b.attrs)+r.NumAttrs())
+
+	for _, attr := range b.attrs {
+		addAttr(attrs, "", attr)
+	}
+
+	prefix := strings.Join(b.groups, ".")
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttr(attrs, prefix, attr)
+
+		return true
+	})
+
+	b.ring.Lock()
+	defer b.ring.Unlock()
+
+	b.ring.lastSeq++
+
+	record := LogRecord{
+		Seq:     b.ring.lastSeq,
+		Time:    r.Time,
+		Level:   r.Level.String(),
+		Message: r.Message,
+		Attrs:   attrs,
+	}
+
+	if b.ring.size <= 0 {
+		return nil
+	}
+
+	if len(b.ring.records) >= b.ring.size {
+		b.ring.records = b.ring.records[1:]
+	}
+	b.ring.records = append(b.ring.records, record)
+
+	return nil
+}
+
+// WithAttrs returns a new [LogBuffer] sharing the same ring buffer, which adds
+// the given attributes to all of its log records.
+func (b *LogBuffer) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefix := strings.Join(b.groups, ".")
+
+	prefixed := make([]slog.Attr, 0, len(b.attrs)+len(attrs))
+	prefixed = append(prefixed, b.attrs...)
+
+	for _, attr := range attrs {
+		if prefix != "" {
+			attr.Key = prefix + "." + attr.Key
+		}
+		prefixed = append(prefixed, attr)
+	}
+
+	return &LogBuffer{
+		ring:   b.ring,
+		level:  b.level,
+		attrs:  prefixed,
+		groups: b.groups,
+	}
+}
+
+// WithGroup returns a new [LogBuffer] sharing the same ring buffer, which
+// prefixes the keys of all following attributes with the group name.
+func (b *LogBuffer) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return b
+	}
+
+	groups := make([]string, len(b.groups), len(b.groups)+1)
+	copy(groups, b.groups)
+
+	return &LogBuffer{
+		ring:   b.ring,
+		level:  b.level,
+		attrs:  b.attrs,
+		groups: append(groups, name),
+	}
+}
+
+// Recent returns (at most) the given amount of the most recent [LogRecord]. A
+// limit of zero or below returns all held [LogRecord]. A nil [LogBuffer] holds
+// no [LogRecord].
+func (b *LogBuffer) Recent(limit int) []LogRecord {
+	if b == nil {
+		return []LogRecord{}
+	}
+
+	b.ring.RLock()
+	defer b.ring.RUnlock()
+
+	records := b.ring.records
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	result := make([]LogRecord, len(records))
+	copy(result, records)
+
+	return result
+}
+
+// Since returns all held [LogRecord] with a sequence number above the given
+// one, as well as the sequence number of the most recent [LogRecord].
+func (b *LogBuffer) Since(seq uint64) ([]LogRecord, uint64) {
+	if b == nil {
+		return []LogRecord{}, seq
+	}
+
+	b.ring.RLock()
+	defer b.ring.RUnlock()
+
+	result := []LogRecord{}
+
+	for _, record := range b.ring.records {
+		if record.Seq > seq {
+			result = append(result, record)
+		}
+	}
+
+	return result, b.ring.lastSeq
+}
+
+// addAttr adds a [slog.Attr] to a map of attributes, flattening any groups
+// into dot-separated keys.
+func addAttr(attrs map[string]any, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	key := attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if key == "" {
+		key = prefix
+	}
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		for _, groupAttr := range value.Group() {
+			addAttr(attrs, key, groupAttr)
+		}
+
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool, slog.KindTime:
+		attrs[key] = value.Any()
+
+	default:
+		attrs[key] = value.String()
+	}
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desertwitch/gover/internal/progress"
+)
+
+const (
+	// wsGUID is the GUID for calculating the handshake's accept key (RFC6455).
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// wsMaxPayload is the maximum payload size accepted for client frames.
+	wsMaxPayload = 1 << 16
+
+	// wsWriteTimeout is the timeout for writing a single frame to a client.
+	wsWriteTimeout = 10 * time.Second
+
+	// wsOpText is the opcode of a WebSocket text frame.
+	wsOpText = 0x1
+
+	// wsOpClose is the opcode of a WebSocket close frame.
+	wsOpClose = 0x8
+
+	// wsOpPing is the opcode of a WebSocket ping frame.
+	wsOpPing = 0x9
+
+	// wsOpPong is the opcode of a WebSocket pong frame.
+	wsOpPong = 0xA
+)
+
+// upgradeWebSocket performs the server-side WebSocket handshake (RFC6455) for
+// a HTTP request, returning the hijacked connection and its buffered IO.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, nil, fmt.Errorf("(api-ws) %w: not an upgrade request", ErrBadHandshake)
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, nil, fmt.Errorf("(api-ws) %w: unsupported version", ErrBadHandshake)
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, nil, fmt.Errorf("(api-ws) %w: missing key", ErrBadHandshake)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("(api-ws) %w: cannot hijack", ErrBadHandshake)
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("(api-ws) failed to hijack: %w", err)
+	}
+
+	hash := sha1.Sum([]byte(key + wsGUID)) //nolint:gosec
+	accept := base64.StdEncoding.EncodeToString(hash[:])
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
+
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+
+		return nil, nil, fmt.Errorf("(api-ws) failed to flush handshake: %w", err)
+	}
+
+	return conn, rw, nil
+}
+
+// writeWebSocketFrame writes a single (final and unmasked) server frame.
+func writeWebSocketFrame(w *bufio.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+
+	switch length := len(payload); {
+	case length < 126: //nolint:mnd
+		header = append(header, byte(length))
+
+	case length <= 0xFFFF: //nolint:mnd
+		header = append(header, 126) //nolint:mnd
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+
+	default:
+		header = append(header, 127) //nolint:mnd
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("(api-ws) failed to write header: %w", err)
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		return fmt.Errorf("(api-ws) failed to write payload: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("(api-ws) failed to flush: %w", err)
+	}
+
+	return nil
+}
+
+// readWebSocketFrame reads a single client frame, returning its opcode and
+// (unmasked) payload. Client frames are required to be masked.
+func readWebSocketFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, fmt.Errorf("(api-ws) failed to read header: %w", err)
+	}
+
+	opcode := header[0] & 0x0F //nolint:mnd
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F) //nolint:mnd
+
+	switch length {
+	case 126: //nolint:mnd
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, fmt.Errorf("(api-ws) failed to read length: %w", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+
+	case 127: //nolint:mnd
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, fmt.Errorf("(api-ws) failed to read length: %w", err)
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked {
+		return 0, nil, fmt.Errorf("(api-ws) %w: unmasked client frame", ErrBadFrame)
+	}
+
+	if length > wsMaxPayload {
+		return 0, nil, fmt.Errorf("(api-ws) %w: payload too large", ErrBadFrame)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, fmt.Errorf("(api-ws) failed to read mask: %w", err)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, fmt.Errorf("(api-ws) failed to read payload: %w", err)
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
+
+// headerContains returns whether a comma-separated HTTP header contains a
+// token (case-insensitive).
+func headerContains(h http.Header, name string, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// handleWebSocket upgrades the request to a WebSocket connection and pushes an
+// [Update] at every interval, until either the client closes the connection or
+// the application's context is cancelled.
+func (h *Handler) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := upgradeWebSocket(w, r)
+	if err != nil {
+		if errors.Is(err, ErrBadHandshake) {
+			writeError(w, http.StatusBadRequest, err)
+		}
+
+		return
+	}
+	h.trackConn(conn)
+	defer h.untrackConn(conn)
+
+	var writeMutex sync.Mutex
+
+	writeFrame := func(opcode byte, payload []byte) error {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+
+		if err := conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+			return fmt.Errorf("(api-ws) failed to set write deadline: %w", err)
+		}
+
+		return writeWebSocketFrame(rw.Writer, opcode, payload)
+	}
+
+	clientDone := make(chan struct{})
+	go func() {
+		defer close(clientDone)
+
+		for {
+			opcode, payload, err := readWebSocketFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+
+			switch opcode {
+			case wsOpClose:
+				_ = writeFrame(wsOpClose, nil)
+
+				return
+
+			case wsOpPing:
+				_ = writeFrame(wsOpPong, payload)
+			}
+		}
+	}()
+
+	var lastSeq uint64
+
+	sendUpdate := func() error {
+		var logs []LogRecord
+		logs, lastSeq = h.logBuffer.Since(lastSeq)
+
+		payload, err := json.Marshal(Update{
+			State:    h.state(),
+			Progress: progress.Collect(h.queueManager),
+			Logs:     logs,
+		})
+		if err != nil {
+			return fmt.Errorf("(api-ws) failed to marshal: %w", err)
+		}
+
+		return writeFrame(wsOpText, payload)
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sendUpdate(); err != nil {
+			return
+		}
+
+		select {
+		case <-clientDone:
+			return
+
+		case <-h.ctx.Done():
+			_ = sendUpdate()
+			_ = writeFrame(wsOpClose, nil)
+
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// trackConn registers a hijacked connection, so that it is closed when the
+// server is shut down (which does not close any hijacked connections itself).
+func (h *Handler) trackConn(conn net.Conn) {
+	h.connsMutex.Lock()
+	defer h.connsMutex.Unlock()
+
+	h.conns[conn] = struct{}{}
+}
+
+// untrackConn closes and unregisters a hijacked connection.
+func (h *Handler) untrackConn(conn net.Conn) {
+	h.connsMutex.Lock()
+	defer h.connsMutex.Unlock()
+
+	delete(h.conns, conn)
+	conn.Close()
+}
+
+// closeConns closes all registered hijacked connections.
+func (h *Handler) closeConns() {
+	h.connsMutex.Lock()
+	defer h.connsMutex.Unlock()
+
+	for conn := range h.conns {
+		conn.Close()
+	}
+}
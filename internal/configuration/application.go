@@ -1,6 +1,7 @@
 package configuration
 
 import (
+	"github.com/desertwitch/gover/internal/filesystem"
 	"github.com/desertwitch/gover/internal/queue"
 	"github.com/desertwitch/gover/internal/schema"
 )
@@ -12,9 +13,26 @@ type PipelineConfiguration struct {
 	IOPipelines          map[string]schema.Pipeline[*schema.Moveable]       // map[targetName]schema.Pipeline
 }
 
+// ConcurrencyConfiguration is a structure holding the worker limits of the
+// operational stages. Any limit of zero (or less) falls back to the default,
+// which is the amount of CPUs (or 1 for IO within a single target).
+type ConcurrencyConfiguration struct {
+	EnumerationWorkers int            // concurrently enumerated sources
+	SourceWorkers      int            // concurrently enumerated shares per source
+	SourceCaps         map[string]int // map[sourceName]workers (overrides SourceWorkers, caps FilterWorkers)
+	FilterWorkers      int            // concurrently established metadata per enumerated share
+	EvaluationWorkers  int            // concurrently evaluated shares
+	ShareWorkers       int            // concurrently evaluated items per share
+	IOWorkers          int            // concurrently processed targets
+	TargetCaps         map[string]int // map[targetName]workers (defaults to 1)
+}
+
 // AppConfiguration is the principal structure holding the application configuration.
 type AppConfiguration struct {
-	Pipelines *PipelineConfiguration
+	Pipelines   *PipelineConfiguration
+	Concurrency *ConcurrencyConfiguration
+	Ordering    func(items []*schema.Moveable) []*schema.Moveable // orders the evaluation and IO queues (nil keeps the enumeration order)
+	Paths       map[string]*filesystem.PathFilter                 // map[shareName]*filesystem.PathFilter
 }
 
 // NewAppConfiguration returns a pointer to a new [AppConfiguration].
@@ -25,5 +43,10 @@ func NewAppConfiguration() *AppConfiguration {
 			EvaluationPipelines:  make(map[string]schema.Pipeline[*schema.Moveable]),
 			IOPipelines:          make(map[string]schema.Pipeline[*schema.Moveable]),
 		},
+		Concurrency: &ConcurrencyConfiguration{
+			SourceCaps: make(map[string]int),
+			TargetCaps: make(map[string]int),
+		},
+		Paths: make(map[string]*filesystem.PathFilter),
 	}
 }
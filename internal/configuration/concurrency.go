@@ -0,0 +1,155 @@
+package configuration
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LimitEnumeration is the stage key for the concurrently enumerated
+	// sources.
+	LimitEnumeration = "enumeration"
+
+	// LimitSource is the stage key for the concurrently enumerated shares per
+	// source.
+	LimitSource = "source"
+
+	// LimitFilter is the stage key for the concurrently established metadata
+	// per enumerated share.
+	LimitFilter = "filter"
+
+	// LimitEvaluation is the stage key for the concurrently evaluated shares.
+	LimitEvaluation = "evaluation"
+
+	// LimitShare is the stage key for the concurrently evaluated items per
+	// share.
+	LimitShare = "share"
+
+	// LimitIO is the stage key for the concurrently processed targets.
+	LimitIO = "io"
+)
+
+// ParseLimits parses worker limits given as comma-separated "name=workers"
+// pairs (e.g. "cache=2,disk1=1") into a map (map[name]workers). An
+// [ErrInvalidLimit] is returned for malformed pairs or non-positive workers.
+func ParseLimits(s string) (map[string]int, error) {
+	limits := make(map[string]int)
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("(config-limits) %w: %q", ErrInvalidLimit, pair)
+		}
+
+		workers, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || workers <= 0 {
+			return nil, fmt.Errorf("(config-limits) %w: %q", ErrInvalidLimit, pair)
+		}
+
+		limits[strings.TrimSpace(name)] = workers
+	}
+
+	return limits, nil
+}
+
+// ValidateStageLimits validates the worker limits of the stages given as a map
+// (map[stageKey]workers). An [ErrInvalidLimit] is returned for an unknown stage
+// key.
+func ValidateStageLimits(limits map[string]int) error {
+	return (&ConcurrencyConfiguration{}).SetStageLimits(limits)
+}
+
+// SetStageLimits sets the worker limits of the stages from a map
+// (map[stageKey]workers). An [ErrInvalidLimit] is returned for an unknown
+// stage key, in which case no limits are set at all.
+func (c *ConcurrencyConfiguration) SetStageLimits(limits map[string]int) error {
+	fields := map[string]*int{
+		LimitEnumeration: &c.EnumerationWorkers,
+		LimitSource:      &c.SourceWorkers,
+		LimitFilter:      &c.FilterWorkers,
+		LimitEvaluation:  &c.EvaluationWorkers,
+		LimitShare:       &c.ShareWorkers,
+		LimitIO:          &c.IOWorkers,
+	}
+
+	for key := range limits {
+		if _, exists := fields[key]; !exists {
+			return fmt.Errorf("(config-limits) %w: unknown stage %q", ErrInvalidLimit, key)
+		}
+	}
+
+	for key, workers := range limits {
+		*fields[key] = workers
+	}
+
+	return nil
+}
+
+// Enumeration returns the amount of concurrently enumerated sources.
+func (c *ConcurrencyConfiguration) Enumeration() int {
+	return orNumCPU(c.EnumerationWorkers)
+}
+
+// Source returns the amount of concurrently enumerated shares on a specific
+// source, preferring its cap (if one is set).
+func (c *ConcurrencyConfiguration) Source(name string) int {
+	if workers, exists := c.SourceCaps[name]; exists && workers > 0 {
+		return workers
+	}
+
+	return orNumCPU(c.SourceWorkers)
+}
+
+// Filter returns the amount of concurrently established metadata for the
+// [schema.Moveable] of an enumerated share on a specific source. It is never
+// more than the cap of that source (if one is set).
+func (c *ConcurrencyConfiguration) Filter(sourceName string) int {
+	workers := orNumCPU(c.FilterWorkers)
+
+	if limit, exists := c.SourceCaps[sourceName]; exists && limit > 0 {
+		return min(workers, limit)
+	}
+
+	return workers
+}
+
+// Evaluation returns the amount of concurrently evaluated shares.
+func (c *ConcurrencyConfiguration) Evaluation() int {
+	return orNumCPU(c.EvaluationWorkers)
+}
+
+// Share returns the amount of concurrently evaluated items per share.
+func (c *ConcurrencyConfiguration) Share() int {
+	return orNumCPU(c.ShareWorkers)
+}
+
+// IO returns the amount of concurrently processed targets.
+func (c *ConcurrencyConfiguration) IO() int {
+	return orNumCPU(c.IOWorkers)
+}
+
+// Target returns the amount of concurrently processed items within a specific
+// target. Unless a cap is set, a target is processed sequentially.
+func (c *ConcurrencyConfiguration) Target(name string) int {
+	if workers, exists := c.TargetCaps[name]; exists && workers > 0 {
+		return workers
+	}
+
+	return 1
+}
+
+// orNumCPU returns the given workers, or the amount of CPUs if not positive.
+func orNumCPU(workers int) int {
+	if workers <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return workers
+}
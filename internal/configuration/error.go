@@ -0,0 +1,17 @@
+package configuration
+
+import "errors"
+
+var (
+	// ErrInvalidLimit occurs when a worker limit is malformed or references an
+	// unknown stage.
+	ErrInvalidLimit = errors.New("invalid worker limit")
+
+	// ErrUnknownKey occurs when a configuration key does not match any of the
+	// known options.
+	ErrUnknownKey = errors.New("unknown configuration key")
+
+	// ErrInvalidValue occurs when a configuration value is not accepted by its
+	// respective option.
+	ErrInvalidValue = errors.New("invalid configuration value")
+)
@@ -0,0 +1,122 @@
+package configuration
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// EnvPrefix is the prefix of all environment variables holding options.
+	EnvPrefix = "GOVER_"
+
+	// envLayer is the name of the environment layer (as used in errors).
+	envLayer = "environment"
+)
+
+// KeyToFlagName returns the name of the flag for a configuration key (e.g.
+// "SOURCE_WORKERS" is the key for the "source-workers" flag).
+func KeyToFlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// FlagNameToKey returns the configuration key for the name of a flag (e.g.
+// "source-workers" is the flag for the "SOURCE_WORKERS" key).
+func FlagNameToKey(name string) string {
+	return strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+}
+
+// ApplyLayers sets the options (flags) of a [flag.FlagSet] from layered
+// sources, in ascending order of precedence:
+//   - The defaults, as defined by the flags themselves.
+//   - A configuration file (unless the filename is empty).
+//   - The environment (variables prefixed with [EnvPrefix]).
+//   - The flags that were explicitly set on the command-line.
+//
+// Keys not matching any flag result in an [ErrUnknownKey], values not accepted
+// by a flag in an [ErrInvalidValue]. All of these are joined and returned
+// together, after all layers were applied. Ignored keys are neither applied
+// nor reported (e.g. for a flag holding the configuration file's path).
+func (c *Handler) ApplyLayers(flags *flag.FlagSet, filename string, environ []string, ignored ...string) error {
+	explicit := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+
+	var errs []error
+
+	if filename != "" {
+		values, err := c.ReadGeneric(filename)
+		if err != nil {
+			return fmt.Errorf("(config-layers) %w", err)
+		}
+
+		errs = append(errs, applyLayer(flags, filename, values, ignored)...)
+	}
+
+	errs = append(errs, applyLayer(flags, envLayer, envValues(environ), ignored)...)
+
+	for name, value := range explicit {
+		if err := flags.Set(name, value); err != nil {
+			errs = append(errs, fmt.Errorf("command-line: %w: -%s=%q (%w)", ErrInvalidValue, name, value, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("(config-layers) %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// applyLayer sets the flags of a [flag.FlagSet] from a map (map[key]value) of
+// one layer, returning all errors that occurred (sorted by their keys).
+func applyLayer(flags *flag.FlagSet, layer string, values map[string]string, ignored []string) []error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+		name := KeyToFlagName(key)
+		if slices.Contains(ignored, name) {
+			continue
+		}
+
+		if flags.Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("%s: %w: %s", layer, ErrUnknownKey, key))
+
+			continue
+		}
+
+		if err := flags.Set(name, values[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w: %s=%q (%w)", layer, ErrInvalidValue, key, values[key], err))
+		}
+	}
+
+	return errs
+}
+
+// envValues returns a map (map[key]value) of all environment variables that
+// are prefixed with [EnvPrefix], with the keys no longer containing the
+// prefix.
+func envValues(environ []string) map[string]string {
+	values := make(map[string]string)
+
+	for _, env := range environ {
+		key, value, found := strings.Cut(env, "=")
+		if !found || !strings.HasPrefix(key, EnvPrefix) {
+			continue
+		}
+
+		values[strings.TrimPrefix(key, EnvPrefix)] = value
+	}
+
+	return values
+}
@@ -0,0 +1,18 @@
+package eviction
+
+import "errors"
+
+var (
+	// ErrInvalidWatermarks occurs when watermarks are malformed, out of range
+	// or with the low watermark not below the high watermark.
+	ErrInvalidWatermarks = errors.New("invalid watermarks")
+
+	// ErrLowWatermarkReached occurs when a [schema.Moveable] is no longer moved
+	// off its pool, because the pool has already reached its low watermark.
+	ErrLowWatermarkReached = errors.New("low watermark reached")
+
+	// ErrNotSelected occurs when a [schema.Moveable] is not moved off its pool,
+	// because it was not selected for the eviction of the pool (as the pool is
+	// not above its high watermark, or enough others were selected).
+	ErrNotSelected = errors.New("not selected for eviction")
+)
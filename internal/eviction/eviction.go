@@ -0,0 +1,318 @@
+// Package eviction implements routines for moving files off pools only once
+// these exceed a high watermark of usage, and only as much as is needed to
+// return them below a low watermark, starting with the least recently used (or
+// oldest) files.
+package eviction
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// ByAtime selects the least recently used (accessed) files first.
+	ByAtime = "atime"
+
+	// ByMtime selects the oldest (modified) files first.
+	ByMtime = "mtime"
+
+	// percent is the factor of a ratio to a percentage.
+	percent = 100
+)
+
+// diskStatProvider defines methods needed to retrieve the usage of a pool.
+type diskStatProvider interface {
+	GetDiskUsage(s schema.Storage) (filesystem.DiskStats, error)
+}
+
+// Handler is the principal implementation of the eviction services. It is safe
+// for concurrent use.
+//
+// The eviction is only active once [Handler.Select] was called, so that a
+// [Handler] has no effect for any operations not selecting candidates first.
+type Handler struct {
+	sync.RWMutex
+
+	// An implementation of [diskStatProvider] for the pool usage.
+	diskStatHandler diskStatProvider
+
+	// The [Watermarks] by the names of the pools (or [AllPools]).
+	watermarks map[string]Watermarks
+
+	// The time the candidates are selected by ([ByAtime] or [ByMtime]).
+	by string
+
+	// The selected [schema.Moveable] (nil while the eviction is not active).
+	selected map[*schema.Moveable]struct{}
+
+	// The names of the pools that have reached their low watermark.
+	reached map[string]struct{}
+}
+
+// NewHandler returns a pointer to a new eviction [Handler] for the given
+// [Watermarks], selecting candidates by the given time ([ByAtime] or
+// [ByMtime]).
+func NewHandler(diskStatHandler diskStatProvider, watermarks map[string]Watermarks, by string) *Handler {
+	return &Handler{
+		diskStatHandler: diskStatHandler,
+		watermarks:      watermarks,
+		by:              by,
+		reached:         make(map[string]struct{}),
+	}
+}
+
+// Select activates the eviction and selects the [schema.Moveable] that are to
+// be moved off their pools, out of all given candidates. For every pool above
+// its high watermark, just enough candidates are selected (in order of their
+// time) to bring the pool below its low watermark. No candidates are selected
+// off pools that are not above their high watermark. The errors of any pools
+// that could not be evaluated (with no candidates selected off them) are
+// returned.
+func (h *Handler) Select(candidates []*schema.Moveable) error {
+	h.Lock()
+	defer h.Unlock()
+
+	h.selected = make(map[*schema.Moveable]struct{})
+
+	byPool := make(map[schema.Storage][]*schema.Moveable)
+	for _, m := range candidates {
+		if _, ok := h.watermarksOf(m.Source); ok {
+			byPool[m.Source] = append(byPool[m.Source], m)
+		}
+	}
+
+	var errs []error
+
+	for pool, items := range byPool {
+		w, _ := h.watermarksOf(pool)
+
+		needed, err := h.bytesAboveLow(pool, w)
+		if err != nil {
+			slog.Warn("Skipped eviction of pool: failed to get usage",
+				"err", err,
+				"pool", pool.GetName(),
+			)
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if needed == 0 {
+			slog.Info("Eviction of pool not needed: below high watermark",
+				"pool", pool.GetName(),
+				"high", w.High,
+			)
+
+			continue
+		}
+
+		selected, selectedBytes := h.selectItems(items, needed)
+
+		slog.Info("Evicting from pool:",
+			"pool", pool.GetName(),
+			"high", w.High,
+			"low", w.Low,
+			"needed", needed,
+			"selected", selected,
+			"selectedBytes", selectedBytes,
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+// selectItems selects the [schema.Moveable] of a pool in order of their time,
+// until at least the needed bytes are selected. It returns the amount of
+// selected [schema.Moveable] and their bytes.
+func (h *Handler) selectItems(items []*schema.Moveable, needed uint64) (int, uint64) {
+	slices.SortStableFunc(items, func(a, b *schema.Moveable) int {
+		return cmp.Compare(h.timeOf(a), h.timeOf(b))
+	})
+
+	var selectedBytes uint64
+	selected := 0
+
+	for _, m := range items {
+		if selectedBytes >= needed {
+			break
+		}
+
+		h.selected[m] = struct{}{}
+		selected++
+
+		if m.Metadata != nil && !m.Metadata.IsDir {
+			selectedBytes += m.Metadata.Size
+		}
+	}
+
+	return selected, selectedBytes
+}
+
+// PreProcessor returns a [schema.BatchProcessor] for the evaluation, which
+// removes (and logs) all [schema.Moveable] off pools with [Watermarks] that
+// were not selected by [Handler.Select], so that these are filtered. It keeps
+// all [schema.Moveable] while the eviction is not active.
+func (h *Handler) PreProcessor() schema.BatchProcessor[*schema.Moveable] {
+	return func(items []*schema.Moveable) ([]*schema.Moveable, bool) {
+		h.RLock()
+		defer h.RUnlock()
+
+		if h.selected == nil {
+			return items, true
+		}
+
+		kept := make([]*schema.Moveable, 0, len(items))
+
+		for _, m := range items {
+			if _, ok := h.watermarksOf(m.Source); ok {
+				if _, selected := h.selected[m]; !selected {
+					slog.Info("Skipped job: not selected for eviction",
+						"reason", fmt.Errorf("(eviction) %w: %s", ErrNotSelected, m.Source.GetName()),
+						"job", m.SourcePath,
+						"share", m.Share.GetName(),
+					)
+
+					continue
+				}
+			}
+
+			kept = append(kept, m)
+		}
+
+		return kept, true
+	}
+}
+
+// Processor returns a [schema.Processor] for the IO, which re-checks the usage
+// of the pool of a [schema.Moveable] and skips it (with [ErrLowWatermarkReached])
+// once the pool has reached its low watermark (for example due to other files
+// having been removed from it in the meantime). It processes all
+// [schema.Moveable] while the eviction is not active.
+func (h *Handler) Processor() schema.Processor[*schema.Moveable] {
+	return func(m *schema.Moveable) error {
+		w, evicting, reached := h.evictionOf(m.Source)
+		if !evicting {
+			return nil
+		}
+
+		if !reached {
+			if stats, err := h.diskStatHandler.GetDiskUsage(m.Source); err != nil || usedPercent(stats) >= w.Low {
+				return nil
+			}
+
+			h.markReached(m.Source, w)
+		}
+
+		reason := fmt.Errorf("(eviction) %w: %s", ErrLowWatermarkReached, m.Source.GetName())
+
+		slog.Info("Skipped job: pool no longer needs eviction",
+			"reason", reason,
+			"job", m.SourcePath,
+			"share", m.Share.GetName(),
+		)
+
+		return reason
+	}
+}
+
+// evictionOf returns the [Watermarks] of a pool, whether files are being
+// evicted from it and whether it has already reached its low watermark.
+func (h *Handler) evictionOf(pool schema.Storage) (Watermarks, bool, bool) {
+	h.RLock()
+	defer h.RUnlock()
+
+	w, ok := h.watermarksOf(pool)
+	if h.selected == nil || !ok {
+		return Watermarks{}, false, false
+	}
+
+	_, reached := h.reached[pool.GetName()]
+
+	return w, true, reached
+}
+
+// markReached records that a pool has reached its low watermark, logging it
+// only once (for the first of possibly concurrent observations).
+func (h *Handler) markReached(pool schema.Storage, w Watermarks) {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, reached := h.reached[pool.GetName()]; reached {
+		return
+	}
+
+	slog.Info("Evicting from pool done: low watermark reached",
+		"pool", pool.GetName(),
+		"low", w.Low,
+	)
+	h.reached[pool.GetName()] = struct{}{}
+}
+
+// watermarksOf returns the [Watermarks] of a [schema.Storage], which is either
+// a pool with its own [Watermarks] or any pool with [Watermarks] for
+// [AllPools] set.
+func (h *Handler) watermarksOf(s schema.Storage) (Watermarks, bool) {
+	if s == nil {
+		return Watermarks{}, false
+	}
+
+	if w, ok := h.watermarks[s.GetName()]; ok {
+		return w, true
+	}
+
+	if pool, isPool := s.(schema.Pool); isPool && pool.IsPool() {
+		if w, ok := h.watermarks[AllPools]; ok {
+			return w, true
+		}
+	}
+
+	return Watermarks{}, false
+}
+
+// bytesAboveLow returns the bytes needed to be moved off a pool to bring it
+// below its low watermark, or zero if it is not above its high watermark.
+func (h *Handler) bytesAboveLow(pool schema.Storage, w Watermarks) (uint64, error) {
+	stats, err := h.diskStatHandler.GetDiskUsage(pool)
+	if err != nil {
+		return 0, fmt.Errorf("(eviction) %w", err)
+	}
+
+	if usedPercent(stats) <= w.High {
+		return 0, nil
+	}
+
+	used := stats.TotalSize - stats.FreeSpace
+	low := uint64(float64(stats.TotalSize) * w.Low / percent)
+
+	return used - min(used, low), nil
+}
+
+// timeOf returns the time (in nanoseconds) a [schema.Moveable] is selected by.
+func (h *Handler) timeOf(m *schema.Moveable) int64 {
+	if m.Metadata == nil {
+		return 0
+	}
+
+	if h.by == ByMtime {
+		return m.Metadata.ModifiedAt.Nano()
+	}
+
+	return m.Metadata.AccessedAt.Nano()
+}
+
+// usedPercent returns the used space of [filesystem.DiskStats] in percent of
+// the total size.
+func usedPercent(stats filesystem.DiskStats) float64 {
+	if stats.TotalSize == 0 {
+		return 0
+	}
+
+	return float64(stats.TotalSize-min(stats.TotalSize, stats.FreeSpace)) / float64(stats.TotalSize) * percent
+}
@@ -0,0 +1,59 @@
+package eviction
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AllPools is the pool name of watermarks applying to all pools.
+	AllPools = "*"
+
+	// maxPercent is the upper bound of a watermark.
+	maxPercent = 100
+)
+
+// Watermarks are the usage thresholds of a pool (in percent of its total size),
+// with moving off the pool starting above the high watermark and stopping once
+// below the low watermark. It is meant to be passed by value.
+type Watermarks struct {
+	High float64
+	Low  float64
+}
+
+// ParseWatermarks parses watermarks given as comma-separated "pool=high:low"
+// pairs (e.g. "cache=85:65,*=90:70") into a map (map[poolName]Watermarks).
+// An [ErrInvalidWatermarks] is returned for malformed pairs, watermarks outside
+// of 0-100 or a low watermark that is not below the high watermark.
+func ParseWatermarks(s string) (map[string]Watermarks, error) {
+	watermarks := make(map[string]Watermarks)
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		high, low, hasLow := strings.Cut(value, ":")
+
+		if !found || !hasLow || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("(eviction-watermarks) %w: %q", ErrInvalidWatermarks, pair)
+		}
+
+		w := Watermarks{}
+		var errHigh, errLow error
+
+		w.High, errHigh = strconv.ParseFloat(strings.TrimSpace(high), 64)
+		w.Low, errLow = strconv.ParseFloat(strings.TrimSpace(low), 64)
+
+		if errHigh != nil || errLow != nil || w.Low < 0 || w.High > maxPercent || w.Low >= w.High {
+			return nil, fmt.Errorf("(eviction-watermarks) %w: %q", ErrInvalidWatermarks, pair)
+		}
+
+		watermarks[strings.TrimSpace(name)] = w
+	}
+
+	return watermarks, nil
+}
@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"log/slog"
@@ -83,3 +84,40 @@ func (f *Handler) walkParentDirs(m *schema.Moveable, basePath string) error {
 
 	return nil
 }
+
+// FindFilesWithSuffix returns the paths of all regular files below a root path
+// with a name ending with the given suffix. Directories that cannot be read are
+// skipped (and logged).
+func (f *Handler) FindFilesWithSuffix(ctx context.Context, root string, suffix string) ([]string, error) {
+	var paths []string
+
+	err := f.fileWalkHandler.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			slog.Warn("Skipped path: failed to read",
+				"path", path,
+				"err", err,
+			)
+
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Type().IsRegular() && strings.HasSuffix(d.Name(), suffix) {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("(fs-findsuffix) failed to walk: %w", err)
+	}
+
+	return paths, nil
+}
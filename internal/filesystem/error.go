@@ -10,4 +10,8 @@ var (
 	// ErrInvalidFileSize is an error that occurs when a given filesize is
 	// smaller than 0 and impossible to handle in the respective function.
 	ErrInvalidFileSize = errors.New("invalid file size < 0")
+
+	// ErrInvalidPattern is an error that occurs when a [PathPattern] is
+	// malformed and cannot be matched.
+	ErrInvalidPattern = errors.New("invalid path pattern")
 )
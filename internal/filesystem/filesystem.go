@@ -10,7 +10,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"runtime"
+	"sync"
 
 	"github.com/desertwitch/gover/internal/schema"
 	"golang.org/x/sys/unix"
@@ -99,71 +99,59 @@ func NewHandler(ctx context.Context, osHandler osProvider, unixHandler unixProvi
 // For convenience, a destination [schema.Storage] can be set here, if it is
 // already known at the time. An example case would be directly allocating to
 // one [schema.Pool] instead of multiple [schema.Disk].
-func (f *Handler) GetMoveables(ctx context.Context, share schema.Share, src schema.Storage, dst schema.Storage) ([]*schema.Moveable, error) {
-	moveables := []*schema.Moveable{}
-
+//
+// The given [PathFilter] (if not nil) and any ignore files (see
+// [IgnoreFileName]) are applied while walking, with any directories excluded
+// by them as a whole not being descended into at all.
+//
+// The metadata of the candidates is established concurrently, with at most
+// maxWorkers at the same time. The errors of any paths and candidates left out
+// due to failures are returned separately from the error of a failed walk.
+func (f *Handler) GetMoveables(ctx context.Context, share schema.Share, src schema.Storage, dst schema.Storage, paths *PathFilter, maxWorkers int) ([]*schema.Moveable, []error, error) {
 	shareDir := filepath.Join(src.GetFSPath(), share.GetName())
 
-	err := f.fileWalkHandler.WalkDir(shareDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			if path != shareDir {
-				slog.Warn("Failure for path during walking of directory tree (was skipped)",
-					"path", path,
-					"err", err,
-					"share", share.GetName(),
-				)
-			}
-
-			return nil
-		}
+	walk := &shareWalk{
+		ctx:       ctx,
+		handler:   f,
+		share:     share,
+		src:       src,
+		dst:       dst,
+		shareDir:  shareDir,
+		paths:     paths,
+		ignores:   newIgnoreTree(shareDir),
+		moveables: []*schema.Moveable{},
+	}
 
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
+	if err := f.fileWalkHandler.WalkDir(shareDir, walk.visit); err != nil {
+		return nil, nil, fmt.Errorf("(fs) failed walking: %w", err)
+	}
 
-		isEmptyDir := false
-		if d.IsDir() {
-			isEmptyDir, err = f.IsEmptyFolder(path)
-			if err != nil {
-				slog.Warn("Failure checking for emptiness during walking of directory tree (was skipped)",
-					"path", path,
-					"err", err,
-					"share", share.GetName(),
-				)
+	moveables := walk.moveables
 
-				return nil
-			}
-		}
+	var skippedMutex sync.Mutex
+	skipped := walk.skipped
 
-		if !d.IsDir() || (d.IsDir() && isEmptyDir) {
-			moveable := &schema.Moveable{
-				Share:      share,
-				Source:     src,
-				SourcePath: path,
-				Dest:       dst,
-			}
+	skip := func(err error) bool {
+		skippedMutex.Lock()
+		defer skippedMutex.Unlock()
 
-			moveables = append(moveables, moveable)
-		}
+		skipped = append(skipped, err)
 
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("(fs) failed walking: %w", err)
+		return false
 	}
 
-	filtered, err := concFilterSlice(ctx, runtime.NumCPU(), moveables, func(m *schema.Moveable) bool {
+	filtered, err := concFilterSlice(ctx, maxWorkers, moveables, func(m *schema.Moveable) bool {
 		if err := f.establishMetadata(m); err != nil {
-			return false
+			return skip(err)
 		}
 		if err := f.establishRelatedDirs(m, shareDir); err != nil {
-			return false
+			return skip(err)
 		}
 
 		return true
 	})
 	if err != nil {
-		return nil, fmt.Errorf("(fs) failed relating metadata: %w", err)
+		return nil, nil, fmt.Errorf("(fs) failed relating metadata: %w", err)
 	}
 
 	establishSymlinks(filtered, dst)
@@ -172,7 +160,7 @@ func (f *Handler) GetMoveables(ctx context.Context, share schema.Share, src sche
 	filtered = removeInternalLinks(filtered)
 	filtered = f.removeInUseFiles(filtered)
 
-	return filtered, nil
+	return filtered, skipped, nil
 }
 
 // removeInUseFiles removes from a slice of [schema.Moveable] these which are
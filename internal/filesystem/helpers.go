@@ -2,12 +2,15 @@ package filesystem
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"sync"
 
 	"github.com/desertwitch/gover/internal/schema"
+	"github.com/zeebo/blake3"
 )
 
 // IsInUse checks if a file is in use by another process of the operating
@@ -63,6 +66,59 @@ func (f *Handler) Exists(path string) (bool, error) {
 	return true, nil
 }
 
+// Remove is a helper function removing a file (or an empty directory).
+func (f *Handler) Remove(path string) error {
+	if err := f.osHandler.Remove(path); err != nil {
+		return fmt.Errorf("(fs-remove) %w", err)
+	}
+
+	return nil
+}
+
+// GetMetadata is a helper function retrieving the current [schema.Metadata] of
+// a path from the filesystem (without following any symbolic links).
+func (f *Handler) GetMetadata(path string) (*schema.Metadata, error) {
+	metadata, err := f.getMetadata(path)
+	if err != nil {
+		return nil, fmt.Errorf("(fs-getmetadata) %w", err)
+	}
+
+	return metadata, nil
+}
+
+// GetChecksum is a helper function retrieving the blake3 checksum of a file.
+func (f *Handler) GetChecksum(path string) (string, error) {
+	file, err := f.osHandler.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("(fs-getchecksum) failed to open: %w", err)
+	}
+	defer file.Close()
+
+	hasher := blake3.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("(fs-getchecksum) failed to hash: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// GetPrefixChecksum is a helper function retrieving the blake3 checksum of the
+// first size bytes of a file, failing if the file is smaller than that.
+func (f *Handler) GetPrefixChecksum(path string, size uint64) (string, error) {
+	file, err := f.osHandler.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("(fs-getprefixchecksum) failed to open: %w", err)
+	}
+	defer file.Close()
+
+	hasher := blake3.New()
+	if _, err := io.CopyN(hasher, file, int64(size)); err != nil { //nolint:gosec
+		return "", fmt.Errorf("(fs-getprefixchecksum) failed to hash: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 // handleSize converts a int64 filesize to a uint64 filesize (with sizes < 0
 // becoming 0).
 func handleSize(size int64) uint64 {
@@ -0,0 +1,193 @@
+package filesystem
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// IgnoreFileName is the name of the per-directory ignore files, which hold
+	// gitignore-style patterns of the paths beneath their directory that are
+	// never to be moved. The ignore files themselves are never moved either.
+	IgnoreFileName = ".goverignore"
+)
+
+// ignoreRule is a single gitignore-style pattern of an [ignoreFile].
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreFile holds the [ignoreRule] of an ignore file, which are relative to
+// the directory containing it.
+type ignoreFile struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// ignoreTree holds all [ignoreFile] encountered while walking a directory tree,
+// by the paths of their directories.
+type ignoreTree struct {
+	root  string
+	files map[string]*ignoreFile
+}
+
+// newIgnoreTree returns a pointer to a new (empty) [ignoreTree] for the
+// directory tree at root.
+func newIgnoreTree(root string) *ignoreTree {
+	return &ignoreTree{
+		root:  root,
+		files: make(map[string]*ignoreFile),
+	}
+}
+
+// load reads the ignore file of a directory (if it has one) into the
+// [ignoreTree], which must happen before any of its children are checked.
+func (t *ignoreTree) load(osHandler osProvider, dir string) error {
+	file, err := osHandler.Open(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("(fs-ignore) failed to open: %w", err)
+	}
+	defer file.Close()
+
+	ignores, err := parseIgnoreFile(file, dir)
+	if err != nil {
+		return fmt.Errorf("(fs-ignore) failed to read: %w", err)
+	}
+
+	if len(ignores.rules) > 0 {
+		t.files[dir] = ignores
+	}
+
+	return nil
+}
+
+// ignores returns if a path is ignored by the ignore files of any of its parent
+// directories. As with gitignore, the last matching pattern decides, with the
+// patterns of deeper ignore files taking precedence over shallower ones.
+func (t *ignoreTree) ignores(path string, isDir bool) bool {
+	if len(t.files) == 0 {
+		return false
+	}
+
+	var files []*ignoreFile
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if file, exists := t.files[dir]; exists {
+			files = append(files, file)
+		}
+
+		if dir == t.root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	ignored := false
+
+	for i := len(files) - 1; i >= 0; i-- {
+		rel, err := filepath.Rel(files[i].dir, path)
+		if err != nil {
+			continue
+		}
+
+		for _, rule := range files[i].rules {
+			if rule.match(filepath.ToSlash(rel), isDir) {
+				ignored = !rule.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+// match returns if the [ignoreRule] matches a path relative to the directory of
+// its [ignoreFile].
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if !r.anchored {
+		matched, _ := filepath.Match(r.segments[0], filepath.Base(rel))
+
+		return matched
+	}
+
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// parseIgnoreFile parses the gitignore-style patterns of an ignore file of a
+// directory. Empty lines and those starting with "#" are ignored, a leading
+// "!" negates a pattern (re-including what was ignored before), a trailing "/"
+// matches only directories and a leading or inner "/" anchors a pattern to the
+// directory (otherwise it matches the name at any depth beneath it).
+func parseIgnoreFile(r io.Reader, dir string) (*ignoreFile, error) {
+	file := &ignoreFile{dir: dir}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+
+		if pattern, negate := strings.CutPrefix(line, "!"); negate {
+			rule.negate = true
+			line = pattern
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+
+		if pattern, dirOnly := strings.CutSuffix(line, "/"); dirOnly {
+			rule.dirOnly = true
+			line = pattern
+		}
+
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		if line == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(line, "/")
+		if !rule.anchored && rule.segments[0] == anyDepth {
+			rule.anchored = true
+		}
+
+		if !validSegments(rule.segments) {
+			continue
+		}
+
+		file.rules = append(file.rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// validSegments returns if all glob segments are well-formed.
+func validSegments(segments []string) bool {
+	for _, segment := range segments {
+		if _, err := filepath.Match(segment, ""); err != nil || segment == "" {
+			return false
+		}
+	}
+
+	return true
+}
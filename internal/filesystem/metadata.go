@@ -49,6 +49,7 @@ func (f *Handler) getMetadata(path string) (*schema.Metadata, error) {
 		GID:        stat.Gid,
 		AccessedAt: stat.Atim,
 		ModifiedAt: stat.Mtim,
+		ChangedAt:  stat.Ctim,
 		Size:       handleSize(stat.Size),
 		IsDir:      (stat.Mode & unix.S_IFMT) == unix.S_IFDIR,
 		IsSymlink:  (stat.Mode & unix.S_IFMT) == unix.S_IFLNK,
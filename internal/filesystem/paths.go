@@ -0,0 +1,242 @@
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// AllShares is the share key of path patterns applying to all shares.
+	AllShares = "*"
+
+	// regexPrefix is the prefix of a path pattern that is a regular expression.
+	regexPrefix = "re:"
+
+	// anyDepth is the glob segment matching zero or more path segments.
+	anyDepth = "**"
+)
+
+// PathPattern is a pattern matched against share-relative paths (separated by
+// slashes), which is either a glob or a regular expression (prefixed with
+// "re:").
+//
+// A glob without any slash matches the base name of a path or any of its
+// parent directories (e.g. "*.part" or ".Recycle.Bin"), any other glob matches
+// the path or any of its parent directories from the share's root (e.g.
+// "plex/Cache/**"). Within a glob, a "**" segment matches zero or more path
+// segments. A regular expression matches anywhere within the path.
+type PathPattern struct {
+	text     string
+	segments []string
+	regex    *regexp.Regexp
+}
+
+// ParsePathPattern returns a pointer to a new [PathPattern] for the given
+// textual representation, or an [ErrInvalidPattern] if it is malformed.
+func ParsePathPattern(s string) (*PathPattern, error) {
+	s = strings.TrimSpace(s)
+
+	if expr, isRegex := strings.CutPrefix(s, regexPrefix); isRegex {
+		regex, err := regexp.Compile(expr)
+		if err != nil || expr == "" {
+			return nil, fmt.Errorf("(fs-paths) %w: %q", ErrInvalidPattern, s)
+		}
+
+		return &PathPattern{text: s, regex: regex}, nil
+	}
+
+	glob := strings.Trim(s, "/")
+	if glob == "" {
+		return nil, fmt.Errorf("(fs-paths) %w: %q", ErrInvalidPattern, s)
+	}
+
+	segments := strings.Split(glob, "/")
+	if !validSegments(segments) {
+		return nil, fmt.Errorf("(fs-paths) %w: %q", ErrInvalidPattern, s)
+	}
+
+	return &PathPattern{text: s, segments: segments}, nil
+}
+
+// String returns the textual representation of the [PathPattern].
+func (p *PathPattern) String() string {
+	return p.text
+}
+
+// Match returns if the [PathPattern] matches a share-relative path.
+func (p *PathPattern) Match(rel string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(rel)
+	}
+
+	pathSegments := strings.Split(strings.Trim(filepath.ToSlash(rel), "/"), "/")
+
+	if len(p.segments) == 1 && p.segments[0] != anyDepth {
+		for _, segment := range pathSegments {
+			if matched, _ := filepath.Match(p.segments[0], segment); matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := 1; i <= len(pathSegments); i++ {
+		if matchSegments(p.segments, pathSegments[:i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDir returns if the [PathPattern] matches a share-relative directory
+// path, so that everything beneath the directory is matched as well.
+func (p *PathPattern) matchDir(rel string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(rel) || p.regex.MatchString(rel+"/")
+	}
+
+	return p.Match(rel)
+}
+
+// matchSegments returns if glob segments match path segments, with a "**" glob
+// segment matching zero or more path segments.
+func matchSegments(globSegments []string, pathSegments []string) bool {
+	for len(globSegments) > 0 {
+		if globSegments[0] == anyDepth {
+			for i := 0; i <= len(pathSegments); i++ {
+				if matchSegments(globSegments[1:], pathSegments[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(pathSegments) == 0 {
+			return false
+		}
+
+		if matched, _ := filepath.Match(globSegments[0], pathSegments[0]); !matched {
+			return false
+		}
+
+		globSegments, pathSegments = globSegments[1:], pathSegments[1:]
+	}
+
+	return len(pathSegments) == 0
+}
+
+// PathFilter holds the include and exclude [PathPattern] of a share, as applied
+// while walking the share in [Handler.GetMoveables]. A nil [PathFilter] keeps
+// all paths.
+type PathFilter struct {
+	includes []*PathPattern
+	excludes []*PathPattern
+}
+
+// NewPathFilter returns a pointer to a new [PathFilter] for the given textual
+// representations of include and exclude [PathPattern].
+func NewPathFilter(includes []string, excludes []string) (*PathFilter, error) {
+	f := &PathFilter{}
+
+	for _, s := range includes {
+		p, err := ParsePathPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		f.includes = append(f.includes, p)
+	}
+
+	for _, s := range excludes {
+		p, err := ParsePathPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		f.excludes = append(f.excludes, p)
+	}
+
+	return f, nil
+}
+
+// prunes returns the exclude [PathPattern] a share-relative directory path is
+// excluded by as a whole (so that its descent can be skipped), or nil if it is
+// not. Includes never prune, as any of the directory's children could still be
+// matched by them.
+func (f *PathFilter) prunes(rel string) *PathPattern {
+	if f == nil {
+		return nil
+	}
+
+	for _, p := range f.excludes {
+		if p.matchDir(rel) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// keeps returns if a share-relative path is not excluded and (if there are any
+// includes) included.
+func (f *PathFilter) keeps(rel string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, p := range f.excludes {
+		if p.Match(rel) {
+			return false
+		}
+	}
+
+	if len(f.includes) == 0 {
+		return true
+	}
+
+	for _, p := range f.includes {
+		if p.Match(rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParsePathPatterns parses path patterns given as "[share:]pattern" entries,
+// separated by newlines or semicolons, into a map (map[shareName][]pattern).
+// Entries without a share apply to all shares (as [AllShares]). Every pattern
+// is validated as a [PathPattern].
+func ParsePathPatterns(s string) (map[string][]string, error) {
+	patterns := make(map[string][]string)
+
+	for entry := range strings.FieldsFuncSeq(s, func(r rune) bool { return r == '\n' || r == ';' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		share, pattern := AllShares, entry
+		if !strings.HasPrefix(entry, regexPrefix) {
+			if name, rest, found := strings.Cut(entry, ":"); found {
+				share, pattern = strings.TrimSpace(name), rest
+			}
+		}
+
+		if share == "" {
+			return nil, fmt.Errorf("(fs-paths) %w: %q has an empty share", ErrInvalidPattern, entry)
+		}
+
+		p, err := ParsePathPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns[share] = append(patterns[share], p.String())
+	}
+
+	return patterns, nil
+}
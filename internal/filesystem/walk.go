@@ -0,0 +1,143 @@
+package filesystem
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// shareWalk is the state of walking the directory tree of a [schema.Share] on
+// a [schema.Storage], collecting all [schema.Moveable] candidates.
+type shareWalk struct {
+	ctx       context.Context //nolint:containedctx
+	handler   *Handler
+	share     schema.Share
+	src       schema.Storage
+	dst       schema.Storage
+	shareDir  string
+	paths     *PathFilter
+	ignores   *ignoreTree
+	moveables []*schema.Moveable
+	skipped   []error
+}
+
+// visit is the [fs.WalkDirFunc] of a [shareWalk], which collects a
+// [schema.Moveable] for every file and empty directory that is not excluded by
+// the [PathFilter] or any ignore file. Paths skipped due to failures are not
+// failing the walk, but their errors are recorded.
+func (w *shareWalk) visit(path string, d fs.DirEntry, err error) error {
+	if err != nil {
+		if path != w.shareDir {
+			slog.Warn("Failure for path during walking of directory tree (was skipped)",
+				"path", path,
+				"err", err,
+				"share", w.share.GetName(),
+			)
+			w.skipped = append(w.skipped, err)
+		}
+
+		return nil
+	}
+
+	if w.ctx.Err() != nil {
+		return w.ctx.Err()
+	}
+
+	if path != w.shareDir {
+		if skip, skipErr := w.excludes(path, d); skip {
+			return skipErr
+		}
+	}
+
+	isEmptyDir := false
+	if d.IsDir() {
+		if err := w.ignores.load(w.handler.osHandler, path); err != nil {
+			slog.Warn("Failure reading ignore file during walking of directory tree (was skipped)",
+				"path", path,
+				"err", err,
+				"share", w.share.GetName(),
+			)
+			w.skipped = append(w.skipped, err)
+
+			return fs.SkipDir
+		}
+
+		isEmptyDir, err = w.handler.IsEmptyFolder(path)
+		if err != nil {
+			slog.Warn("Failure checking for emptiness during walking of directory tree (was skipped)",
+				"path", path,
+				"err", err,
+				"share", w.share.GetName(),
+			)
+			w.skipped = append(w.skipped, err)
+
+			return nil
+		}
+	}
+
+	if !d.IsDir() || isEmptyDir {
+		if rel, _ := filepath.Rel(w.shareDir, path); !w.paths.keeps(rel) {
+			slog.Debug("Filtered by path pattern:",
+				"path", path,
+				"share", w.share.GetName(),
+			)
+
+			return nil
+		}
+
+		w.moveables = append(w.moveables, &schema.Moveable{
+			Share:      w.share,
+			Source:     w.src,
+			SourcePath: path,
+			Dest:       w.dst,
+		})
+	}
+
+	return nil
+}
+
+// excludes returns if a path is excluded from the [shareWalk], along with the
+// error to be returned to the walk (so that excluded directories are pruned).
+func (w *shareWalk) excludes(path string, d fs.DirEntry) (bool, error) {
+	rel, _ := filepath.Rel(w.shareDir, path)
+
+	if !d.IsDir() && d.Name() == IgnoreFileName {
+		return true, nil
+	}
+
+	if w.ignores.ignores(path, d.IsDir()) {
+		slog.Debug("Ignored by ignore file:",
+			"path", path,
+			"share", w.share.GetName(),
+		)
+
+		return true, skipDirIf(d.IsDir())
+	}
+
+	if d.IsDir() {
+		if p := w.paths.prunes(rel); p != nil {
+			slog.Debug("Pruned directory by path pattern:",
+				"path", path,
+				"pattern", p,
+				"share", w.share.GetName(),
+			)
+
+			return true, fs.SkipDir
+		}
+	}
+
+	return false, nil
+}
+
+// skipDirIf returns [fs.SkipDir] for directories, so that their descent is
+// skipped, or nil otherwise.
+func skipDirIf(isDir bool) error {
+	if isDir {
+		return fs.SkipDir
+	}
+
+	return nil
+}
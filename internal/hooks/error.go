@@ -0,0 +1,9 @@
+package hooks
+
+import "errors"
+
+var (
+	// ErrHookFailed occurs when a hook script has exited with a non-zero exit
+	// code (or could not be run at all).
+	ErrHookFailed = errors.New("hook script failed")
+)
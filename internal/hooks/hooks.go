@@ -0,0 +1,174 @@
+// Package hooks implements routines for running local scripts before and after
+// the run, each [schema.Share] and each target [schema.Storage], passing them
+// the context of their [Event] (as environment variables and JSON on stdin).
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/gover/internal/priority"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// RunPre is the hook run before the whole run. A failure fails the run.
+	RunPre = "run-pre"
+
+	// RunPost is the hook run after the whole run.
+	RunPost = "run-post"
+
+	// SharePre is the hook run before a [schema.Share] is enumerated. A failure
+	// skips the [schema.Share].
+	SharePre = "share-pre"
+
+	// SharePost is the hook run after the files of a [schema.Share] were moved.
+	SharePost = "share-post"
+
+	// TargetPost is the hook run after the queue of a target [schema.Storage]
+	// has finished.
+	TargetPost = "target-post"
+
+	// envPrefix is the prefix of the environment variables passed to a hook.
+	// It differs from the prefix of the configuration's environment variables,
+	// so that a hook script running the application is not configured by them.
+	envPrefix = "GOVERHOOK_"
+
+	// shell is the shell the hook scripts are run with.
+	shell = "/bin/sh"
+)
+
+// Event is the context passed to a hook script, both as environment variables
+// (e.g. GOVERHOOK_SHARE) and as JSON on its standard input.
+type Event struct {
+	Hook    string `json:"hook"`
+	Command string `json:"command,omitempty"`
+	Share   string `json:"share,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+	Items   int    `json:"items"`
+	Success int    `json:"success"`
+	Skipped int    `json:"skipped"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+// environ returns the environment variables of an [Event].
+func (e *Event) environ() []string {
+	vars := map[string]string{
+		"HOOK":    e.Hook,
+		"COMMAND": e.Command,
+		"SHARE":   e.Share,
+		"SOURCE":  e.Source,
+		"TARGET":  e.Target,
+		"OUTCOME": e.Outcome,
+		"ITEMS":   strconv.Itoa(e.Items),
+		"SUCCESS": strconv.Itoa(e.Success),
+		"SKIPPED": strconv.Itoa(e.Skipped),
+		"BYTES":   strconv.FormatUint(e.Bytes, 10),
+	}
+
+	environ := make([]string, 0, len(vars))
+	for key, value := range vars {
+		environ = append(environ, envPrefix+key+"="+value)
+	}
+
+	return environ
+}
+
+// Handler is the principal implementation for the hook services. It is safe
+// for concurrent use.
+type Handler struct {
+	// The hook scripts by the names of their hooks (e.g. [RunPre]).
+	scripts map[string]string
+
+	// The time a hook script may run before it is killed.
+	timeout time.Duration
+
+	// The scheduling settings applied to the hook scripts.
+	sched priority.Settings
+}
+
+// NewHandler returns a pointer to a new hook [Handler] for the given hook
+// scripts (map[hookName]script), which may run at most for the given timeout
+// and with the given [priority.Settings].
+func NewHandler(scripts map[string]string, timeout time.Duration, sched priority.Settings) *Handler {
+	return &Handler{
+		scripts: scripts,
+		timeout: timeout,
+		sched:   sched,
+	}
+}
+
+// Has returns whether a hook script is set for a hook.
+func (h *Handler) Has(hook string) bool {
+	return strings.TrimSpace(h.scripts[hook]) != ""
+}
+
+// Run runs the hook script of an [Event]'s hook (if any is set) with the
+// [Event] as its context, returning an [ErrHookFailed] if it fails or exits
+// with a non-zero exit code. The hook script is run (with the shell) regardless
+// of any cancellation of the given context, so that post hooks always run, but
+// it is killed once the timeout of the [Handler] has passed.
+func (h *Handler) Run(ctx context.Context, e *Event) error {
+	if !h.Has(e.Hook) {
+		return nil
+	}
+
+	stdin, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("(hooks) failed to marshal: %w", err)
+	}
+
+	hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, shell, "-c", h.scripts[e.Hook])
+	cmd.Env = append(os.Environ(), e.environ()...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	slog.Info("Running hook:",
+		"hook", e.Hook,
+		"share", e.Share,
+		"target", e.Target,
+	)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("(hooks) %w: %s: %w", ErrHookFailed, e.Hook, err)
+	}
+
+	// A hook script may already have exited.
+	if err := h.sched.ApplyPID(cmd.Process.Pid); err != nil && !errors.Is(err, unix.ESRCH) {
+		slog.Warn("Failed to set the scheduling priorities of hook (skipped)",
+			"err", err,
+			"hook", e.Hook,
+		)
+	}
+
+	err = cmd.Wait()
+	if output.Len() > 0 {
+		slog.Debug("Hook output:",
+			"hook", e.Hook,
+			"output", strings.TrimSpace(output.String()),
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("(hooks) %w: %s: %w", ErrHookFailed, e.Hook, err)
+	}
+
+	return nil
+}
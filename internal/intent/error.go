@@ -0,0 +1,14 @@
+package intent
+
+import "errors"
+
+var (
+	// ErrChecksumMismatch occurs when the destination of a half-done move no
+	// longer matches the checksum recorded in its intent.
+	ErrChecksumMismatch = errors.New("destination checksum mismatch")
+
+	// ErrSourceMissing occurs when a half-done move cannot be rolled back,
+	// because its source is no longer present (and its destination is not
+	// known to be complete).
+	ErrSourceMissing = errors.New("source missing")
+)
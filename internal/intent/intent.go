@@ -0,0 +1,175 @@
+// Package intent implements a durable write-ahead log of the intents of the IO
+// (as JSON Lines), which is written (and synced) before every step of a move
+// that changes the filesystem. After a crash (e.g. a loss of power), the intents
+// of all moves that were not done are replayed to finish or roll back these
+// half-done moves, before any new moves are made.
+package intent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// OpMkdir is the intent of creating a directory of the directory structure
+	// of a move on its target.
+	OpMkdir = "mkdir"
+
+	// OpCopy is the intent of copying a file to its temporary file on the
+	// target.
+	OpCopy = "copy"
+
+	// OpRename is the intent of renaming a copied (and verified) temporary file
+	// to its destination.
+	OpRename = "rename"
+
+	// OpCreate is the intent of creating a directory, hard- or symlink on its
+	// target.
+	OpCreate = "create"
+
+	// OpRemove is the intent of removing the source of a move, once its
+	// destination is complete.
+	OpRemove = "remove"
+
+	// OpDone marks all intents of a destination as done.
+	OpDone = "done"
+
+	// filePerms are the permissions an intent log is created with.
+	filePerms = 0o600
+)
+
+// Record is a single intent of the log, describing the step of a move that is
+// about to be made. All intents of a move are keyed by their destination path.
+type Record struct {
+	Op         string `json:"op"`
+	SourcePath string `json:"sourcePath,omitempty"`
+	DestPath   string `json:"destPath"`
+	TmpPath    string `json:"tmpPath,omitempty"`
+	Checksum   string `json:"checksum,omitempty"`
+	AccessedAt int64  `json:"accessedAt,omitempty"`
+	ModifiedAt int64  `json:"modifiedAt,omitempty"`
+}
+
+// Log is an append-only intent log, with every [Record] being synced to the
+// disk before it returns. It is safe for concurrent use.
+type Log struct {
+	sync.Mutex
+
+	// The path of the intent log.
+	path string
+
+	// The intent log file (nil if the [Log] discards all [Record]).
+	file *os.File
+
+	// Whether the intent log held records (of a failed recovery) on opening.
+	inherited bool
+
+	// The destination paths with intents that are not yet done.
+	pending map[string]struct{}
+}
+
+// Open returns a pointer to a new [Log] appending to the intent log at the
+// given path (creating it if needed). An empty path returns a [Log] discarding
+// all [Record], so that no recovery is possible.
+func Open(path string) (*Log, error) {
+	l := &Log{
+		path:    path,
+		pending: make(map[string]struct{}),
+	}
+
+	if path == "" {
+		return l, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerms)
+	if err != nil {
+		return nil, fmt.Errorf("(intent) failed to open: %w", err)
+	}
+	l.file = f
+
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		l.inherited = true
+	}
+
+	return l, nil
+}
+
+// Intend durably records a [Record], before the step it describes is made.
+func (l *Log) Intend(r *Record) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	if err := l.write(r); err != nil {
+		return err
+	}
+	l.pending[r.DestPath] = struct{}{}
+
+	return nil
+}
+
+// Done durably marks all intents of a destination path as done, once its move
+// has either completed or failed (and was cleaned up).
+func (l *Log) Done(destPath string) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if _, pending := l.pending[destPath]; !pending || l.file == nil {
+		return nil
+	}
+
+	if err := l.write(&Record{Op: OpDone, DestPath: destPath}); err != nil {
+		return err
+	}
+	delete(l.pending, destPath)
+
+	return nil
+}
+
+// Close closes the intent log. If all of its intents are done, the intent log
+// is emptied, as there is nothing left to recover. The intent log is therefore
+// only to be opened by the one instance that may move files at a time.
+func (l *Log) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("(intent) failed to close: %w", err)
+	}
+	l.file = nil
+
+	if len(l.pending) == 0 && !l.inherited {
+		if err := os.Truncate(l.path, 0); err != nil {
+			return fmt.Errorf("(intent) failed to empty: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// write appends a [Record] (as one line) to the intent log and syncs it.
+func (l *Log) write(r *Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("(intent) failed to marshal: %w", err)
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("(intent) failed to write: %w", err)
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("(intent) failed to sync: %w", err)
+	}
+
+	return nil
+}
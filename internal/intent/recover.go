@@ -0,0 +1,357 @@
+package intent
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/zeebo/blake3"
+	"golang.org/x/sys/unix"
+)
+
+// maxLineSize is the maximum size of a single line of an intent log.
+const maxLineSize = 1 << 20
+
+// osProvider defines the operating system methods needed for the recovery.
+type osProvider interface {
+	Open(name string) (*os.File, error)
+	Remove(name string) error
+}
+
+// unixProvider defines the Unix operating system methods needed for the
+// recovery.
+type unixProvider interface {
+	Lstat(path string, stat *unix.Stat_t) error
+	UtimesNano(path string, times []unix.Timespec) error
+}
+
+// Recovery summarizes what was fixed by replaying an intent log.
+type Recovery struct {
+	// Finished is the amount of half-done moves that were finished.
+	Finished int
+
+	// RolledBack is the amount of half-done moves that were rolled back.
+	RolledBack int
+
+	// TempsRemoved is the amount of stray temporary files that were removed.
+	TempsRemoved int
+
+	// DirsRemoved is the amount of stray (empty) directories that were
+	// removed.
+	DirsRemoved int
+
+	// Failed is the amount of intents that could not be recovered.
+	Failed int
+}
+
+// Fixed returns the amount of all fixes of the [Recovery].
+func (r *Recovery) Fixed() int {
+	return r.Finished + r.RolledBack + r.TempsRemoved + r.DirsRemoved
+}
+
+// Handler is the principal implementation of the recovery services.
+type Handler struct {
+	osHandler   osProvider
+	unixHandler unixProvider
+}
+
+// NewHandler returns a pointer to a new recovery [Handler].
+func NewHandler(osHandler osProvider, unixHandler unixProvider) *Handler {
+	return &Handler{
+		osHandler:   osHandler,
+		unixHandler: unixHandler,
+	}
+}
+
+// Recover replays the intent log at the given path, finishing or rolling back
+// all half-done moves (of intents that are not done) and removing any of their
+// stray temporary files and directories:
+//   - A move with its source still present is rolled back, unless only the
+//     removal of its source was left to do and its destination still matches
+//     its checksum, in which case the move is finished.
+//   - A move with its source no longer present is finished (by restoring the
+//     timestamps of its destination), if only the removal of its source was
+//     left to do and its destination still matches its checksum. Any other
+//     such move cannot be recovered, with its destination being kept.
+//
+// The intent log is emptied once everything was recovered, otherwise it is
+// kept for another recovery. A missing (or empty) intent log has nothing to
+// recover.
+func (h *Handler) Recover(path string) (*Recovery, error) {
+	pending, err := readPending(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery := &Recovery{}
+
+	if len(pending) == 0 {
+		return recovery, nil
+	}
+
+	// Directories are removed last and deepest first, once they could be empty.
+	sort.SliceStable(pending, func(i, j int) bool {
+		if (pending[i].Op == OpMkdir) != (pending[j].Op == OpMkdir) {
+			return pending[j].Op == OpMkdir
+		}
+
+		return pending[i].Op == OpMkdir && len(pending[i].DestPath) > len(pending[j].DestPath)
+	})
+
+	for _, r := range pending {
+		if err := h.recoverRecord(r, recovery); err != nil {
+			slog.Warn("Failure recovering from intent log (skipped)",
+				"err", err,
+				"op", r.Op,
+				"path", r.DestPath,
+				"job", r.SourcePath,
+			)
+			recovery.Failed++
+		}
+	}
+
+	if recovery.Failed == 0 {
+		if err := os.Truncate(path, 0); err != nil {
+			return recovery, fmt.Errorf("(intent) failed to empty: %w", err)
+		}
+	}
+
+	return recovery, nil
+}
+
+// recoverRecord finishes or rolls back the half-done move of the last intent
+// [Record] of a destination.
+func (h *Handler) recoverRecord(r *Record, recovery *Recovery) error {
+	if r.TmpPath != "" {
+		removed, err := h.remove(r.TmpPath)
+		if err != nil {
+			return err
+		}
+		if removed {
+			slog.Info("Recovered: removed stray temporary file",
+				"path", r.TmpPath,
+			)
+			recovery.TempsRemoved++
+		}
+	}
+
+	switch r.Op {
+	case OpMkdir:
+		return h.recoverMkdir(r, recovery)
+
+	case OpRename, OpCreate:
+		return h.rollback(r, recovery)
+
+	case OpRemove:
+		return h.recoverRemove(r, recovery)
+	}
+
+	return nil
+}
+
+// recoverMkdir removes a directory created for a half-done move, unless it is
+// no longer empty (being used by other moves).
+func (h *Handler) recoverMkdir(r *Record, recovery *Recovery) error {
+	removed, err := h.remove(r.DestPath)
+	if err != nil {
+		return err
+	}
+
+	if removed {
+		slog.Info("Recovered: removed stray directory",
+			"path", r.DestPath,
+		)
+		recovery.DirsRemoved++
+	}
+
+	return nil
+}
+
+// rollback removes the destination of a half-done move, provided that its
+// source is still present. Otherwise the destination is kept, and an error
+// ([ErrSourceMissing]) is returned, so that the intent log is kept as well.
+func (h *Handler) rollback(r *Record, recovery *Recovery) error {
+	srcExists, err := h.exists(r.SourcePath)
+	if err != nil {
+		return err
+	}
+	if !srcExists {
+		return fmt.Errorf("(intent) %w: %s", ErrSourceMissing, r.SourcePath)
+	}
+
+	removed, err := h.remove(r.DestPath)
+	if err != nil {
+		return err
+	}
+
+	if removed {
+		slog.Info("Recovered: rolled back half-done move",
+			"path", r.DestPath,
+			"job", r.SourcePath,
+		)
+		recovery.RolledBack++
+	}
+
+	return nil
+}
+
+// recoverRemove finishes a half-done move, of which only the removal of its
+// source was left to do. A file destination has to match its checksum, or the
+// move is rolled back instead.
+func (h *Handler) recoverRemove(r *Record, recovery *Recovery) error {
+	dstExists, err := h.exists(r.DestPath)
+	if err != nil || !dstExists {
+		return err
+	}
+
+	if r.Checksum != "" {
+		checksum, err := h.checksum(r.DestPath)
+		if err != nil {
+			return err
+		}
+
+		if checksum != r.Checksum {
+			mismatch := fmt.Errorf("(intent) %w: %s != %s", ErrChecksumMismatch, checksum, r.Checksum)
+
+			slog.Warn("Rolling back half-done move: destination does not match",
+				"err", mismatch,
+				"path", r.DestPath,
+				"job", r.SourcePath,
+			)
+
+			if err := h.rollback(r, recovery); err != nil {
+				return fmt.Errorf("%w: %w", mismatch, err)
+			}
+
+			return nil
+		}
+	}
+
+	if _, err := h.remove(r.SourcePath); err != nil {
+		return err
+	}
+
+	if r.Checksum != "" {
+		ts := []unix.Timespec{unix.NsecToTimespec(r.AccessedAt), unix.NsecToTimespec(r.ModifiedAt)}
+		if err := h.unixHandler.UtimesNano(r.DestPath, ts); err != nil {
+			return fmt.Errorf("(intent) failed to utimesnano: %w", err)
+		}
+	}
+
+	slog.Info("Recovered: finished half-done move",
+		"path", r.DestPath,
+		"job", r.SourcePath,
+	)
+	recovery.Finished++
+
+	return nil
+}
+
+// exists returns whether a path exists (without following symlinks).
+func (h *Handler) exists(path string) (bool, error) {
+	var st unix.Stat_t
+
+	if err := h.unixHandler.Lstat(path, &st); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("(intent) failed to lstat: %w", err)
+	}
+
+	return true, nil
+}
+
+// remove removes a path, returning whether it was removed. A path that does
+// not exist (or is a directory that is not empty) is not removed.
+func (h *Handler) remove(path string) (bool, error) {
+	if err := h.osHandler.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, unix.ENOTEMPTY) || errors.Is(err, unix.EEXIST) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("(intent) failed to remove: %w", err)
+	}
+
+	return true, nil
+}
+
+// checksum returns the blake3 checksum of a file.
+func (h *Handler) checksum(path string) (string, error) {
+	f, err := h.osHandler.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("(intent) failed to open: %w", err)
+	}
+	defer f.Close()
+
+	hasher := blake3.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", fmt.Errorf("(intent) failed to hash: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// readPending reads the intent log at the given path, returning the last
+// [Record] of every destination that is not done (in order of the log). A
+// torn (partially written) last line is ignored.
+func readPending(path string) ([]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("(intent) failed to open: %w", err)
+	}
+	defer f.Close()
+
+	last := make(map[string]*Record)
+	var order []string
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxLineSize)
+
+	for scanner.Scan() {
+		r := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), r); err != nil {
+			slog.Warn("Skipped intent: malformed record",
+				"err", err,
+			)
+
+			continue
+		}
+
+		if r.Op == OpDone {
+			delete(last, r.DestPath)
+
+			continue
+		}
+
+		if _, seen := last[r.DestPath]; !seen {
+			order = append(order, r.DestPath)
+		}
+		last[r.DestPath] = r
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("(intent) failed to read: %w", err)
+	}
+
+	pending := make([]*Record, 0, len(last))
+	for _, destPath := range order {
+		if r, exists := last[destPath]; exists {
+			pending = append(pending, r)
+			delete(last, destPath)
+		}
+	}
+
+	return pending, nil
+}
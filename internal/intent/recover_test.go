@@ -0,0 +1,294 @@
+package intent
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/blake3"
+)
+
+// testPaths are the paths of a single move within a temporary directory.
+type testPaths struct {
+	src string
+	dst string
+	tmp string
+	dir string
+}
+
+func newTestPaths(t *testing.T) testPaths {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "dst"), 0o755))
+
+	return testPaths{
+		src: filepath.Join(root, "src", "file"),
+		dst: filepath.Join(root, "dst", "file"),
+		tmp: filepath.Join(root, "dst", "file.gover"),
+		dir: filepath.Join(root, "dst", "dir"),
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func writeLog(t *testing.T, records []*Record, trailer string) string {
+	t.Helper()
+
+	var b strings.Builder
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		require.NoError(t, err)
+		b.Write(append(line, '\n'))
+	}
+	b.WriteString(trailer)
+
+	path := filepath.Join(t.TempDir(), "intent.log")
+	writeFile(t, path, b.String())
+
+	return path
+}
+
+func checksumOf(content string) string {
+	sum := blake3.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRecover(t *testing.T) {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, p testPaths) ([]*Record, string)
+		want     Recovery
+		exist    func(p testPaths) []string
+		notExist func(p testPaths) []string
+		check    func(t *testing.T, p testPaths)
+	}{
+		{
+			name: "copy",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.src, "data")
+				writeFile(t, p.tmp, "da")
+
+				return []*Record{
+					{Op: OpCopy, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+				}, ""
+			},
+			want:     Recovery{TempsRemoved: 1},
+			exist:    func(p testPaths) []string { return []string{p.src} },
+			notExist: func(p testPaths) []string { return []string{p.tmp, p.dst} },
+		},
+		{
+			name: "rename",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.src, "data")
+				writeFile(t, p.dst, "data")
+
+				return []*Record{
+					{Op: OpCopy, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+					{Op: OpRename, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+				}, ""
+			},
+			want:     Recovery{RolledBack: 1},
+			exist:    func(p testPaths) []string { return []string{p.src} },
+			notExist: func(p testPaths) []string { return []string{p.tmp, p.dst} },
+		},
+		{
+			name: "remove with matching checksum",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.src, "data")
+				writeFile(t, p.dst, "data")
+
+				return []*Record{
+					{Op: OpRename, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+					{Op: OpRemove, SourcePath: p.src, DestPath: p.dst, Checksum: checksumOf("data"), AccessedAt: modified.UnixNano(), ModifiedAt: modified.UnixNano()},
+				}, ""
+			},
+			want:     Recovery{Finished: 1},
+			exist:    func(p testPaths) []string { return []string{p.dst} },
+			notExist: func(p testPaths) []string { return []string{p.src} },
+			check: func(t *testing.T, p testPaths) {
+				t.Helper()
+
+				info, err := os.Stat(p.dst)
+				require.NoError(t, err)
+				assert.True(t, info.ModTime().Equal(modified))
+			},
+		},
+		{
+			name: "remove with mismatching checksum",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.src, "data")
+				writeFile(t, p.dst, "corrupt")
+
+				return []*Record{
+					{Op: OpRemove, SourcePath: p.src, DestPath: p.dst, Checksum: checksumOf("data")},
+				}, ""
+			},
+			want:     Recovery{RolledBack: 1},
+			exist:    func(p testPaths) []string { return []string{p.src} },
+			notExist: func(p testPaths) []string { return []string{p.dst} },
+		},
+		{
+			name: "remove with mismatching checksum and missing source",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.dst, "corrupt")
+
+				return []*Record{
+					{Op: OpRemove, SourcePath: p.src, DestPath: p.dst, Checksum: checksumOf("data")},
+				}, ""
+			},
+			want:     Recovery{Failed: 1},
+			exist:    func(p testPaths) []string { return []string{p.dst} },
+			notExist: func(p testPaths) []string { return []string{p.src} },
+		},
+		{
+			name: "rename with missing source",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.dst, "data")
+
+				return []*Record{
+					{Op: OpRename, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+				}, ""
+			},
+			want:     Recovery{Failed: 1},
+			exist:    func(p testPaths) []string { return []string{p.dst} },
+			notExist: func(p testPaths) []string { return []string{p.src} },
+		},
+		{
+			name: "mkdir",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				require.NoError(t, os.Mkdir(p.dir, 0o755))
+
+				return []*Record{
+					{Op: OpMkdir, SourcePath: p.src, DestPath: p.dir},
+				}, ""
+			},
+			want:     Recovery{DirsRemoved: 1},
+			notExist: func(p testPaths) []string { return []string{p.dir} },
+		},
+		{
+			name: "torn last line",
+			setup: func(t *testing.T, p testPaths) ([]*Record, string) {
+				writeFile(t, p.src, "data")
+				writeFile(t, p.tmp, "da")
+
+				return []*Record{
+					{Op: OpCopy, SourcePath: p.src, DestPath: p.dst, TmpPath: p.tmp},
+				}, `{"op":"rename","sourcePath":"` + p.src
+			},
+			want:     Recovery{TempsRemoved: 1},
+			exist:    func(p testPaths) []string { return []string{p.src} },
+			notExist: func(p testPaths) []string { return []string{p.tmp, p.dst} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPaths(t)
+			records, trailer := tt.setup(t, p)
+			logPath := writeLog(t, records, trailer)
+
+			recovery, err := NewHandler(&schema.OS{}, &schema.Unix{}).Recover(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *recovery)
+
+			if tt.exist != nil {
+				for _, path := range tt.exist(p) {
+					assert.FileExists(t, path)
+				}
+			}
+
+			if tt.notExist != nil {
+				for _, path := range tt.notExist(p) {
+					assert.NoFileExists(t, path)
+					assert.NoDirExists(t, path)
+				}
+			}
+
+			if tt.check != nil {
+				tt.check(t, p)
+			}
+
+			info, err := os.Stat(logPath)
+			require.NoError(t, err)
+
+			if tt.want.Failed > 0 {
+				assert.NotZero(t, info.Size(), "intent log was emptied")
+			} else {
+				assert.Zero(t, info.Size(), "intent log was not emptied")
+			}
+		})
+	}
+}
+
+func TestRecoverMissingLog(t *testing.T) {
+	recovery, err := NewHandler(&schema.OS{}, &schema.Unix{}).Recover(filepath.Join(t.TempDir(), "missing.log"))
+	require.NoError(t, err)
+	assert.Equal(t, Recovery{}, *recovery)
+}
+
+func TestReadPending(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []*Record
+		trailer string
+		want    []*Record
+	}{
+		{
+			name: "last record per destination",
+			records: []*Record{
+				{Op: OpCopy, DestPath: "/a", TmpPath: "/a.gover"},
+				{Op: OpCreate, DestPath: "/b"},
+				{Op: OpRename, DestPath: "/a", TmpPath: "/a.gover"},
+			},
+			want: []*Record{
+				{Op: OpRename, DestPath: "/a", TmpPath: "/a.gover"},
+				{Op: OpCreate, DestPath: "/b"},
+			},
+		},
+		{
+			name: "done destinations",
+			records: []*Record{
+				{Op: OpCopy, DestPath: "/a", TmpPath: "/a.gover"},
+				{Op: OpCreate, DestPath: "/b"},
+				{Op: OpDone, DestPath: "/a"},
+			},
+			want: []*Record{
+				{Op: OpCreate, DestPath: "/b"},
+			},
+		},
+		{
+			name: "torn last line",
+			records: []*Record{
+				{Op: OpCopy, DestPath: "/a", TmpPath: "/a.gover"},
+			},
+			trailer: `{"op":"done","destPa`,
+			want: []*Record{
+				{Op: OpCopy, DestPath: "/a", TmpPath: "/a.gover"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending, err := readPending(writeLog(t, tt.records, tt.trailer))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pending)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"sort"
 
+	"github.com/desertwitch/gover/internal/intent"
 	"github.com/desertwitch/gover/internal/schema"
 )
 
@@ -17,7 +18,18 @@ func (i *Handler) ensureDirectoryStructure(m *schema.Moveable, job *ioReport) er
 
 	for dir != nil {
 		if _, err := i.osHandler.Stat(dir.DestPath); errors.Is(err, fs.ErrNotExist) {
-			if err := i.unixHandler.Mkdir(dir.DestPath, dir.Metadata.Perms); err != nil {
+			if err := i.intend(intent.OpMkdir, dir, "", ""); err != nil {
+				return fmt.Errorf("(io-ensuredirs) failed to log intent: %w", err)
+			}
+
+			if err := i.unixHandler.Mkdir(dir.DestPath, dir.Metadata.Perms); errors.Is(err, fs.ErrExist) {
+				// Created in the meantime by a concurrent worker on the same target.
+				i.intendDirDone(dir)
+				job.DirsWalked = append(job.DirsWalked, dir)
+				dir = dir.Child
+
+				continue
+			} else if err != nil {
 				return fmt.Errorf("(io-ensuredirs) failed to mkdir %s: %w", dir.DestPath, err)
 			}
 
@@ -48,6 +60,11 @@ func (i *Handler) cleanDirectoryStructure(batch *ioReport) {
 	})
 
 	removed := make(map[string]struct{})
+	var removedDirs []*schema.Directory
+
+	defer func() {
+		i.journalRemovedDirs(removedDirs)
+	}()
 
 	for _, dir := range batch.DirsWalked {
 		if _, alreadyRemoved := removed[dir.SourcePath]; alreadyRemoved {
@@ -85,20 +102,24 @@ func (i *Handler) cleanDirectoryStructure(batch *ioReport) {
 			}
 
 			removed[dir.SourcePath] = struct{}{}
+			removedDirs = append(removedDirs, dir)
 		}
 	}
 }
 
-// cleanDirectoriesAfterFailure deletes after a failure the created empty
-// directory structure (on target).
-func (i *Handler) cleanDirectoriesAfterFailure(job *ioReport) {
-	sort.Slice(job.DirsCreated, func(i, j int) bool {
-		return calculateDirectoryDepth(job.DirsCreated[i]) > calculateDirectoryDepth(job.DirsCreated[j])
+// cleanDirectoriesAfterFailure deletes the directory structure created (on
+// target) for failed operations, where it has remained empty. It is only called
+// once all operations on the target have finished, so that no directory is
+// removed while another (concurrent) operation still relies on it, and before
+// the timestamps are ensured (as removing a directory changes its parent's).
+func (i *Handler) cleanDirectoriesAfterFailure(batch *ioReport) {
+	sort.Slice(batch.DirsFailed, func(i, j int) bool {
+		return calculateDirectoryDepth(batch.DirsFailed[i]) > calculateDirectoryDepth(batch.DirsFailed[j])
 	})
 
 	removed := make(map[string]struct{})
 
-	for _, dir := range job.DirsCreated {
+	for _, dir := range batch.DirsFailed {
 		if _, alreadyRemoved := removed[dir.DestPath]; alreadyRemoved {
 			continue
 		}
@@ -134,6 +155,17 @@ func (i *Handler) cleanDirectoriesAfterFailure(job *ioReport) {
 			}
 
 			removed[dir.DestPath] = struct{}{}
+
+			continue
+		}
+
+		// Kept for (and written to by) another operation, so it is treated
+		// like a directory created for that one.
+		if err := i.ensureTimestamp(dir.DestPath, dir.Metadata); err != nil {
+			slog.Warn("Failure setting a timestamp (was skipped)",
+				"path", dir.DestPath,
+				"err", err,
+			)
 		}
 	}
 }
@@ -3,6 +3,14 @@ package io
 import "errors"
 
 var (
+	// ErrPipePreProcFailed occurs when the pre-processing pipeline of a target
+	// storage has failed.
+	ErrPipePreProcFailed = errors.New("pre-processing pipeline has failed")
+
+	// ErrPipePostProcFailed occurs when the post-processing pipeline of a
+	// target storage has failed.
+	ErrPipePostProcFailed = errors.New("post-processing pipeline has failed")
+
 	// ErrSourceFileInUse is an error that occurs when the source file is
 	// already in use.
 	ErrSourceFileInUse = errors.New("source file is currently in use")
@@ -9,15 +9,22 @@ import (
 	"io/fs"
 	"os"
 
+	"github.com/desertwitch/gover/internal/intent"
 	"github.com/desertwitch/gover/internal/schema"
 	"github.com/zeebo/blake3"
 )
 
 // contextReader is an implementation of [io.Reader] that is Context-aware for
-// receiving mid-transfer cancellation.
+// receiving mid-transfer cancellation. The read bytes are throttled with a
+// [throttleProvider] (for a source and target) and then counted towards an
+// [ioTargetQueue], so that its throughput reflects the throttled rate.
 type contextReader struct {
-	ctx    context.Context //nolint:containedctx
-	reader io.Reader
+	ctx         context.Context //nolint:containedctx
+	reader      io.Reader
+	throttle    throttleProvider
+	source      string
+	target      string
+	targetQueue ioTargetQueue
 }
 
 // Read wraps the [io.Reader] reading function while being aware of and handling
@@ -27,30 +34,45 @@ func (cr *contextReader) Read(p []byte) (int, error) {
 	case <-cr.ctx.Done():
 		return 0, context.Canceled
 	default:
-		return cr.reader.Read(p) //nolint:wrapcheck
 	}
+
+	n, err := cr.reader.Read(p)
+
+	if n > 0 {
+		if cr.throttle.Wait(cr.ctx, cr.source, cr.target, n) != nil {
+			return n, context.Canceled
+		}
+		cr.targetQueue.AddBytesCopied(uint64(n))
+	}
+
+	return n, err //nolint:wrapcheck
 }
 
 // moveFile is the principal method for moving a file-type [schema.Moveable].
-func (i *Handler) moveFile(ctx context.Context, m *schema.Moveable) error {
+// It returns the (verified) blake3 checksum of the moved file.
+func (i *Handler) moveFile(ctx context.Context, m *schema.Moveable, targetQueue ioTargetQueue) (string, error) {
 	var transferComplete bool
 
 	srcFile, err := i.osHandler.Open(m.SourcePath)
 	if err != nil {
-		return fmt.Errorf("(io-movefile) failed to open src: %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to open src: %w", err)
 	}
 	defer srcFile.Close()
 
-	tmpPath := m.DestPath + ".gover"
+	tmpPath := m.DestPath + TempFileSuffix
 	defer func() {
 		if !transferComplete {
 			_ = i.osHandler.Remove(tmpPath)
 		}
 	}()
 
+	if err := i.intend(intent.OpCopy, m, tmpPath, ""); err != nil {
+		return "", fmt.Errorf("(io-movefile) failed to log intent: %w", err)
+	}
+
 	dstFile, err := i.osHandler.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, os.FileMode(m.Metadata.Perms))
 	if err != nil {
-		return fmt.Errorf("(io-movefile) failed to open dst: %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to open dst: %w", err)
 	}
 	defer dstFile.Close()
 
@@ -58,41 +80,49 @@ func (i *Handler) moveFile(ctx context.Context, m *schema.Moveable) error {
 	dstHasher := blake3.New()
 
 	ctxReader := &contextReader{
-		ctx:    ctx,
-		reader: io.TeeReader(srcFile, srcHasher),
+		ctx:         ctx,
+		reader:      io.TeeReader(srcFile, srcHasher),
+		throttle:    i.throttleHandler,
+		source:      m.Source.GetName(),
+		target:      m.Dest.GetName(),
+		targetQueue: targetQueue,
 	}
 	multiWriter := io.MultiWriter(dstFile, dstHasher)
 
 	if _, err := io.Copy(multiWriter, ctxReader); err != nil {
 		if errors.Is(err, context.Canceled) {
-			return fmt.Errorf("(io-movefile) canceled: %w", err)
+			return "", fmt.Errorf("(io-movefile) canceled: %w", err)
 		}
 
-		return fmt.Errorf("(io-movefile) failed to copy: %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to copy: %w", err)
 	}
 
 	if err := dstFile.Sync(); err != nil {
-		return fmt.Errorf("(io-movefile) failed to sync dst: %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to sync dst: %w", err)
 	}
 
 	srcChecksum := hex.EncodeToString(srcHasher.Sum(nil))
 	dstChecksum := hex.EncodeToString(dstHasher.Sum(nil))
 
 	if srcChecksum != dstChecksum {
-		return fmt.Errorf("(io-movefile) %w: %s (src) != %s (dst)", ErrHashMismatch, srcChecksum, dstChecksum)
+		return "", fmt.Errorf("(io-movefile) %w: %s (src) != %s (dst)", ErrHashMismatch, srcChecksum, dstChecksum)
 	}
 
 	if _, err := i.osHandler.Stat(m.DestPath); err == nil {
-		return fmt.Errorf("(io-movefile) %w", ErrRenameExists)
+		return "", fmt.Errorf("(io-movefile) %w", ErrRenameExists)
 	} else if !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("(io-movefile) failed to stat (pre rename existence): %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to stat (pre rename existence): %w", err)
+	}
+
+	if err := i.intend(intent.OpRename, m, tmpPath, dstChecksum); err != nil {
+		return "", fmt.Errorf("(io-movefile) failed to log intent: %w", err)
 	}
 
 	if err := i.osHandler.Rename(tmpPath, m.DestPath); err != nil {
-		return fmt.Errorf("(io-movefile) failed to rename tmp file to dst file: %w", err)
+		return "", fmt.Errorf("(io-movefile) failed to rename tmp file to dst file: %w", err)
 	}
 
 	transferComplete = true
 
-	return nil
+	return dstChecksum, nil
 }
@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"log/slog"
 
+	"github.com/desertwitch/gover/internal/intent"
 	"github.com/desertwitch/gover/internal/schema"
 	"golang.org/x/sys/unix"
 )
@@ -28,29 +29,61 @@ func (i *Handler) cleanFileAfterFailure(m *schema.Moveable) {
 
 // processFile is the principal method for IO-processing a file-type
 // [schema.Moveable]. Apart from moving the file itself, it handles both
-// spacing, permissioning and cleanup.
-func (i *Handler) processFile(ctx context.Context, m *schema.Moveable) error {
-	enoughSpace, err := i.fsHandler.HasEnoughFreeSpace(m.Dest, m.Share.GetSpaceFloor(), m.Metadata.Size)
+// spacing, permissioning and cleanup. It returns the blake3 checksum of the
+// moved file.
+func (i *Handler) processFile(ctx context.Context, m *schema.Moveable, targetQueue ioTargetQueue) (string, error) {
+	release, err := i.reserveSpace(m)
 	if err != nil {
-		return fmt.Errorf("(io-file) failed to check enough space: %w", err)
-	}
-	if !enoughSpace {
-		return fmt.Errorf("(io-file) %w", ErrNotEnoughSpace)
+		return "", err
 	}
+	defer release()
 
-	if err := i.moveFile(ctx, m); err != nil {
-		return fmt.Errorf("(io-file) failed to move file: %w", err)
+	checksum, err := i.moveFile(ctx, m, targetQueue)
+	if err != nil {
+		return "", fmt.Errorf("(io-file) failed to move file: %w", err)
 	}
 
 	if err := i.ensurePermissions(m.DestPath, m.Metadata); err != nil {
-		return fmt.Errorf("(io-file) failed to ensure permissions: %w", err)
+		return "", fmt.Errorf("(io-file) failed to ensure permissions: %w", err)
+	}
+
+	if err := i.intend(intent.OpRemove, m, "", checksum); err != nil {
+		return "", fmt.Errorf("(io-file) failed to log intent: %w", err)
 	}
 
 	if err := i.osHandler.Remove(m.SourcePath); err != nil {
-		return fmt.Errorf("(io-file) failed to remove src after move: %w", err)
+		return "", fmt.Errorf("(io-file) failed to remove src after move: %w", err)
 	}
 
-	return nil
+	return checksum, nil
+}
+
+// reserveSpace checks that the destination [schema.Storage] of a file-type
+// [schema.Moveable] has enough free space for it, on top of the space already
+// reserved by other files being moved there (concurrently), and reserves its
+// space until the returned function is called (once it was moved or failed).
+func (i *Handler) reserveSpace(m *schema.Moveable) (func(), error) {
+	i.Lock()
+	defer i.Unlock()
+
+	name := m.Dest.GetName()
+
+	enoughSpace, err := i.fsHandler.HasEnoughFreeSpace(m.Dest, m.Share.GetSpaceFloor(), i.reserved[name]+m.Metadata.Size)
+	if err != nil {
+		return nil, fmt.Errorf("(io-file) failed to check enough space: %w", err)
+	}
+	if !enoughSpace {
+		return nil, fmt.Errorf("(io-file) %w", ErrNotEnoughSpace)
+	}
+
+	i.reserved[name] += m.Metadata.Size
+
+	return func() {
+		i.Lock()
+		defer i.Unlock()
+
+		i.reserved[name] -= m.Metadata.Size
+	}, nil
 }
 
 // processDirectory is the principal method for IO-processing a directory-type
@@ -59,11 +92,28 @@ func (i *Handler) processFile(ctx context.Context, m *schema.Moveable) error {
 func (i *Handler) processDirectory(m *schema.Moveable) error {
 	dirExisted := false
 
-	if err := i.unixHandler.Mkdir(m.DestPath, m.Metadata.Perms); err != nil {
-		if !errors.Is(err, unix.EEXIST) {
-			return fmt.Errorf("(io-dir) failed to mkdir: %w", err)
-		}
+	if _, err := i.osHandler.Stat(m.DestPath); err == nil {
 		dirExisted = true
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("(io-dir) failed to stat (existence): %w", err)
+	}
+
+	// Only a directory that is created here may be removed by a recovery.
+	if !dirExisted {
+		if err := i.intend(intent.OpCreate, m, "", ""); err != nil {
+			return fmt.Errorf("(io-dir) failed to log intent: %w", err)
+		}
+
+		if err := i.unixHandler.Mkdir(m.DestPath, m.Metadata.Perms); err != nil {
+			if !errors.Is(err, unix.EEXIST) {
+				return fmt.Errorf("(io-dir) failed to mkdir: %w", err)
+			}
+			dirExisted = true
+
+			if err := i.intentHandler.Done(m.DestPath); err != nil {
+				return fmt.Errorf("(io-dir) failed to log intent: %w", err)
+			}
+		}
 	}
 
 	if !dirExisted {
@@ -72,6 +122,10 @@ func (i *Handler) processDirectory(m *schema.Moveable) error {
 		}
 	}
 
+	if err := i.intend(intent.OpRemove, m, "", ""); err != nil {
+		return fmt.Errorf("(io-dir) failed to log intent: %w", err)
+	}
+
 	if err := i.osHandler.Remove(m.SourcePath); err != nil {
 		return fmt.Errorf("(io-dir) failed to remove src after move: %w", err)
 	}
@@ -83,6 +137,10 @@ func (i *Handler) processDirectory(m *schema.Moveable) error {
 // [schema.Moveable]. Apart from recreating the hardlink itself, it handles both
 // permissioning and cleanup as well.
 func (i *Handler) processHardlink(m *schema.Moveable) error {
+	if err := i.intend(intent.OpCreate, m, "", ""); err != nil {
+		return fmt.Errorf("(io-hardl) failed to log intent: %w", err)
+	}
+
 	if err := i.unixHandler.Link(m.HardlinkTo.DestPath, m.DestPath); err != nil {
 		return fmt.Errorf("(io-hardl) failed to link: %w", err)
 	}
@@ -91,6 +149,10 @@ func (i *Handler) processHardlink(m *schema.Moveable) error {
 		return fmt.Errorf("(io-hardl) failed to ensure permissions: %w", err)
 	}
 
+	if err := i.intend(intent.OpRemove, m, "", ""); err != nil {
+		return fmt.Errorf("(io-hardl) failed to log intent: %w", err)
+	}
+
 	if err := i.osHandler.Remove(m.SourcePath); err != nil {
 		return fmt.Errorf("(io-hardl) failed to remove src after move: %w", err)
 	}
@@ -102,6 +164,10 @@ func (i *Handler) processHardlink(m *schema.Moveable) error {
 // [schema.Moveable]. Apart from recreating the symlink itself, it handles both
 // permissioning and cleanup as well.
 func (i *Handler) processSymlink(m *schema.Moveable, internalLink bool) error {
+	if err := i.intend(intent.OpCreate, m, "", ""); err != nil {
+		return fmt.Errorf("(io-syml) failed to log intent: %w", err)
+	}
+
 	if internalLink {
 		if err := i.unixHandler.Symlink(m.SymlinkTo.DestPath, m.DestPath); err != nil {
 			return fmt.Errorf("(io-syml) failed to symlink: %w", err)
@@ -116,6 +182,10 @@ func (i *Handler) processSymlink(m *schema.Moveable, internalLink bool) error {
 		return fmt.Errorf("(io-syml) failed to ensure permissions: %w", err)
 	}
 
+	if err := i.intend(intent.OpRemove, m, "", ""); err != nil {
+		return fmt.Errorf("(io-syml) failed to log intent: %w", err)
+	}
+
 	if err := i.osHandler.Remove(m.SourcePath); err != nil {
 		return fmt.Errorf("(io-syml) failed to remove src after move: %w", err)
 	}
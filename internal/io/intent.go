@@ -0,0 +1,58 @@
+package io
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/intent"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// intend durably records the intent of an IO operation on a filesystem
+// element with the [intentProvider], before the operation is made.
+func (i *Handler) intend(op string, elem fsElement, tmpPath string, checksum string) error {
+	r := &intent.Record{
+		Op:         op,
+		SourcePath: elem.GetSourcePath(),
+		DestPath:   elem.GetDestPath(),
+		TmpPath:    tmpPath,
+		Checksum:   checksum,
+	}
+
+	if metadata := elem.GetMetadata(); metadata != nil {
+		r.AccessedAt = metadata.AccessedAt.Nano()
+		r.ModifiedAt = metadata.ModifiedAt.Nano()
+	}
+
+	if err := i.intentHandler.Intend(r); err != nil {
+		return fmt.Errorf("(io-intent) %w", err)
+	}
+
+	return nil
+}
+
+// intendDone marks the intents of a [schema.Moveable] and the directories
+// created for it as done, once it has either been processed or failed (and was
+// cleaned up).
+func (i *Handler) intendDone(m *schema.Moveable, job *ioReport) {
+	for _, dir := range job.DirsCreated {
+		i.intendDirDone(dir)
+	}
+
+	if err := i.intentHandler.Done(m.DestPath); err != nil {
+		slog.Warn("Failure marking intent as done (skipped)",
+			"path", m.DestPath,
+			"err", err,
+		)
+	}
+}
+
+// intendDirDone marks the intents of a [schema.Directory] as done.
+func (i *Handler) intendDirDone(dir *schema.Directory) {
+	if err := i.intentHandler.Done(dir.DestPath); err != nil {
+		slog.Warn("Failure marking intent as done (skipped)",
+			"path", dir.DestPath,
+			"err", err,
+		)
+	}
+}
@@ -5,16 +5,23 @@ package io
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"sync"
 
+	"github.com/desertwitch/gover/internal/intent"
+	"github.com/desertwitch/gover/internal/journal"
 	"github.com/desertwitch/gover/internal/queue"
 	"github.com/desertwitch/gover/internal/schema"
 	"golang.org/x/sys/unix"
 )
 
+// TempFileSuffix is the suffix of the temporary file a file is copied to on
+// its target, before it is renamed to its destination.
+const TempFileSuffix = ".gover"
+
 // fsProvider defines the filesystem methods needed for IO operations.
 type fsProvider interface {
 	HasEnoughFreeSpace(s schema.Storage, minFree uint64, fileSize uint64) (bool, error)
@@ -46,12 +53,33 @@ type unixProvider interface {
 // ioTargetQueue defines the methods an IO queue needs to have for IO
 // operations.
 type ioTargetQueue interface {
+	AddBytesCopied(bytes uint64)
 	AddBytesTransfered(bytes uint64)
-	DequeueAndProcess(ctx context.Context, processFunc func(*schema.Moveable) int) error
+	DequeueAndProcess(ctx context.Context, processFunc func(*schema.Moveable) (int, error)) error
+	DequeueAndProcessConc(ctx context.Context, maxWorkers int, processFunc func(*schema.Moveable) (int, error)) error
 	PreProcess(p schema.Pipeline[*schema.Moveable]) bool
 	PostProcess(p schema.Pipeline[*schema.Moveable]) bool
 }
 
+// intentProvider defines the methods needed to durably record the intents of
+// IO operations, before they are made.
+type intentProvider interface {
+	Intend(r *intent.Record) error
+	Done(destPath string) error
+}
+
+// journalProvider defines the methods needed to journal completed IO
+// operations.
+type journalProvider interface {
+	Write(entries ...*journal.Entry) error
+}
+
+// throttleProvider defines the methods needed to throttle the bandwidth of IO
+// operations.
+type throttleProvider interface {
+	Wait(ctx context.Context, source string, target string, n int) error
+}
+
 // fsElement defines the methods any filesystem element needs to have for IO
 // operations.
 type fsElement interface {
@@ -66,35 +94,58 @@ type fsElement interface {
 type Handler struct {
 	sync.Mutex
 
-	fsHandler   fsProvider
-	osHandler   osProvider
-	unixHandler unixProvider
+	fsHandler       fsProvider
+	osHandler       osProvider
+	unixHandler     unixProvider
+	intentHandler   intentProvider
+	journalHandler  journalProvider
+	throttleHandler throttleProvider
+
+	// The space reserved by files being moved, by target [schema.Storage].
+	reserved map[string]uint64
 }
 
-// NewHandler returns a pointer to a new IO [Handler].
-func NewHandler(fsHandler fsProvider, osHandler osProvider, unixHandler unixProvider) *Handler {
+// NewHandler returns a pointer to a new IO [Handler], which records the intents
+// of all IO operations with the given [intentProvider] (before they are made)
+// and all completed IO operations with the given [journalProvider]. The
+// copying of files is throttled with the given [throttleProvider].
+func NewHandler(fsHandler fsProvider, osHandler osProvider, unixHandler unixProvider, intentHandler intentProvider, journalHandler journalProvider, throttleHandler throttleProvider) *Handler {
 	return &Handler{
-		fsHandler:   fsHandler,
-		osHandler:   osHandler,
-		unixHandler: unixHandler,
+		fsHandler:       fsHandler,
+		osHandler:       osHandler,
+		unixHandler:     unixHandler,
+		intentHandler:   intentHandler,
+		journalHandler:  journalHandler,
+		throttleHandler: throttleHandler,
+		reserved:        make(map[string]uint64),
 	}
 }
 
-// ProcessTargetQueue sequentially processes an [ioTargetQueue], containing
-// [schema.Moveable] grouped by one respective destination [schema.Storage].
+// ProcessTargetQueue processes an [ioTargetQueue], containing [schema.Moveable]
+// grouped by one respective destination [schema.Storage]. The error of every
+// [schema.Moveable] (or of its subelements) is returned to the
+// [ioTargetQueue], while an error is only returned if the [ioTargetQueue] has
+// failed as a whole.
 //
-// This method does not concurrently operate within a single [ioTargetQueue].
-// Hence this function is usually called on multiple [ioTargetQueue]
-// concurrently, but processing each respective [schema.Storage] in sequence.
+// With maxWorkers of 1 (or less), this method does not concurrently operate
+// within a single [ioTargetQueue]. Hence this function is usually called on
+// multiple [ioTargetQueue] concurrently, but processing each respective
+// [schema.Storage] in sequence. A higher maxWorkers should only be used for
+// [schema.Storage] handling concurrent writes well. The space of files being
+// moved is reserved for the free space checks of the concurrent workers, and
+// the directory structure is only cleaned up once all of them have finished.
 func (i *Handler) ProcessTargetQueue(
 	ctx context.Context,
 	pipelines map[string]schema.Pipeline[*schema.Moveable],
 	target schema.Storage,
 	targetQueue ioTargetQueue,
-) bool {
+	maxWorkers int,
+) error {
+	var batchMutex sync.Mutex
 	batch := &ioReport{}
 
 	defer func() {
+		i.cleanDirectoriesAfterFailure(batch)
 		i.ensureTimestamps(batch)
 		i.cleanDirectoryStructure(batch)
 	}()
@@ -105,41 +156,61 @@ func (i *Handler) ProcessTargetQueue(
 				"target", target.GetName(),
 			)
 
-			return false
+			return fmt.Errorf("(io) %w", ErrPipePreProcFailed)
 		}
 	}
 
-	if err := targetQueue.DequeueAndProcess(ctx, func(m *schema.Moveable) int {
+	processFunc := func(m *schema.Moveable) (int, error) {
 		job := &ioReport{}
 
+		defer func() {
+			batchMutex.Lock()
+			mergeIOReports(batch, job)
+			batchMutex.Unlock()
+		}()
+
 		if pipeline, exists := pipelines[target.GetName()]; exists {
-			if success := pipeline.Process(m); !success {
-				return queue.DecisionSkipped
+			if err := pipeline.Process(m); err != nil {
+				return queue.DecisionSkipped, err
 			}
 		}
 
-		if err := i.processElement(ctx, m, job); err != nil {
-			return queue.DecisionSkipped
+		if err := i.processElement(ctx, m, targetQueue, job); err != nil {
+			return queue.DecisionSkipped, err
 		}
 
+		var subErrs []error
+
 		for _, h := range m.Hardlinks {
-			if err := i.processSubElement(ctx, h, m, job); err != nil {
-				continue
+			if err := i.processSubElement(ctx, h, m, targetQueue, job); err != nil {
+				subErrs = append(subErrs, err)
 			}
 		}
 
 		for _, s := range m.Symlinks {
-			if err := i.processSubElement(ctx, s, m, job); err != nil {
-				continue
+			if err := i.processSubElement(ctx, s, m, targetQueue, job); err != nil {
+				subErrs = append(subErrs, err)
 			}
 		}
 
-		mergeIOReports(batch, job)
+		if err := i.journalJob(m, job); err != nil {
+			subErrs = append(subErrs, err)
+		}
+
 		targetQueue.AddBytesTransfered(m.Metadata.Size)
 
-		return queue.DecisionSuccess
-	}); err != nil {
-		return false
+		return queue.DecisionSuccess, errors.Join(subErrs...)
+	}
+
+	var err error
+	if maxWorkers > 1 {
+		err = targetQueue.DequeueAndProcessConc(ctx, maxWorkers, processFunc)
+	} else {
+		err = targetQueue.DequeueAndProcess(ctx, processFunc)
+	}
+
+	if err != nil {
+		return fmt.Errorf("(io) %w", err)
 	}
 
 	if pipeline, exists := pipelines[target.GetName()]; exists {
@@ -148,19 +219,20 @@ func (i *Handler) ProcessTargetQueue(
 				"target", target.GetName(),
 			)
 
-			return false
+			return fmt.Errorf("(io) %w", ErrPipePostProcFailed)
 		}
 	}
 
-	return true
+	return nil
 }
 
 // processElement processes a dequeued "parent" [schema.Moveable] element.
-func (i *Handler) processElement(ctx context.Context, elem *schema.Moveable, job *ioReport) error {
-	if err := i.processMoveable(ctx, elem, job); err != nil {
+func (i *Handler) processElement(ctx context.Context, elem *schema.Moveable, targetQueue ioTargetQueue, job *ioReport) error {
+	if err := i.processMoveable(ctx, elem, targetQueue, job); err != nil {
 		slog.Warn("Skipped job: failure during processing",
 			"path", elem.DestPath,
 			"err", err,
+			"dst", elem.Dest.GetName(),
 			"job", elem.SourcePath,
 			"share", elem.Share.GetName(),
 		)
@@ -179,11 +251,12 @@ func (i *Handler) processElement(ctx context.Context, elem *schema.Moveable, job
 
 // processSubElement processes a dequeued "child" [schema.Moveable]
 // (hard-/symlink) subelement.
-func (i *Handler) processSubElement(ctx context.Context, subelem *schema.Moveable, elem *schema.Moveable, job *ioReport) error {
-	if err := i.processMoveable(ctx, subelem, job); err != nil {
+func (i *Handler) processSubElement(ctx context.Context, subelem *schema.Moveable, elem *schema.Moveable, targetQueue ioTargetQueue, job *ioReport) error {
+	if err := i.processMoveable(ctx, subelem, targetQueue, job); err != nil {
 		slog.Warn("Skipped subjob: failure during processing",
 			"path", subelem.DestPath,
 			"err", err,
+			"dst", elem.Dest.GetName(),
 			"subjob", subelem.SourcePath,
 			"job", elem.SourcePath,
 			"share", elem.Share.GetName(),
@@ -208,8 +281,9 @@ func (i *Handler) processSubElement(ctx context.Context, subelem *schema.Moveabl
 }
 
 // processMoveable is the principal method for IO-processing a [schema.Moveable]
-// of any supported type.
-func (i *Handler) processMoveable(ctx context.Context, m *schema.Moveable, job *ioReport) error {
+// of any supported type. The bytes of a copied file are counted towards the
+// [ioTargetQueue] as they are copied.
+func (i *Handler) processMoveable(ctx context.Context, m *schema.Moveable, targetQueue ioTargetQueue, job *ioReport) error {
 	var jobComplete bool
 
 	intermediateJob := &ioReport{}
@@ -220,8 +294,9 @@ func (i *Handler) processMoveable(ctx context.Context, m *schema.Moveable, job *
 			mergeIOReports(job, intermediateJob)
 		} else {
 			i.cleanFileAfterFailure(m)
-			i.cleanDirectoriesAfterFailure(intermediateJob)
+			job.DirsFailed = append(job.DirsFailed, intermediateJob.DirsCreated...)
 		}
+		i.intendDone(m, intermediateJob)
 	}()
 
 	if inUse := i.fsHandler.IsInUse(m.SourcePath); inUse {
@@ -233,9 +308,11 @@ func (i *Handler) processMoveable(ctx context.Context, m *schema.Moveable, job *
 	}
 
 	if !m.Metadata.IsDir && !m.IsHardlink && !m.IsSymlink && !m.Metadata.IsSymlink {
-		if err := i.processFile(ctx, m); err != nil {
+		checksum, err := i.processFile(ctx, m, targetQueue)
+		if err != nil {
 			return fmt.Errorf("(io) failed to process file: %w", err)
 		}
+		intermediateJob.Checksums = map[*schema.Moveable]string{m: checksum}
 		jobComplete = true
 	}
 
@@ -0,0 +1,115 @@
+package io
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/journal"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// journalJob records all elements created by the completed [ioReport] of a
+// "parent" [schema.Moveable] (including its subelements and directories),
+// returning any failure to do so.
+func (i *Handler) journalJob(m *schema.Moveable, job *ioReport) error {
+	entries := make([]*journal.Entry, 0, len(job.AnyCreated))
+
+	for _, elem := range job.AnyCreated {
+		switch e := elem.(type) {
+		case *schema.Moveable:
+			entries = append(entries, newMoveEntry(e, m, job.Checksums[e]))
+
+		case *schema.Directory:
+			entries = append(entries, &journal.Entry{
+				Op:         journal.OpCreate,
+				Kind:       journal.KindDirectory,
+				Share:      m.Share.GetName(),
+				Source:     m.Source.GetName(),
+				SourcePath: e.SourcePath,
+				Dest:       m.Dest.GetName(),
+				DestPath:   e.DestPath,
+				Metadata:   journal.NewMetadata(e.Metadata),
+			})
+		}
+	}
+
+	return i.writeJournal(entries, m.SourcePath)
+}
+
+// journalRemovedDirs records all source directories removed (once empty) after
+// moving their elements.
+func (i *Handler) journalRemovedDirs(dirs []*schema.Directory) {
+	entries := make([]*journal.Entry, 0, len(dirs))
+
+	for _, dir := range dirs {
+		entries = append(entries, &journal.Entry{
+			Op:         journal.OpRemove,
+			Kind:       journal.KindDirectory,
+			SourcePath: dir.SourcePath,
+			Metadata:   journal.NewMetadata(dir.Metadata),
+		})
+	}
+
+	_ = i.writeJournal(entries, "")
+}
+
+// writeJournal writes [journal.Entry] with the [journalProvider], logging and
+// returning any failure to do so.
+func (i *Handler) writeJournal(entries []*journal.Entry, job string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := i.journalHandler.Write(entries...); err != nil {
+		slog.Warn("Failure writing the journal (skipped)",
+			"err", err,
+			"job", job,
+		)
+
+		return fmt.Errorf("(io) failed writing journal: %w", err)
+	}
+
+	return nil
+}
+
+// newMoveEntry returns a pointer to a new [journal.Entry] of a moved
+// [schema.Moveable] (or a subelement of the "parent" [schema.Moveable], which
+// it shares the [schema.Share] and [schema.Storage] with) with its checksum (if
+// it is a file).
+func newMoveEntry(m *schema.Moveable, parent *schema.Moveable, checksum string) *journal.Entry {
+	e := &journal.Entry{
+		Op:         journal.OpMove,
+		Kind:       journal.KindFile,
+		Share:      parent.Share.GetName(),
+		Source:     parent.Source.GetName(),
+		SourcePath: m.SourcePath,
+		Dest:       parent.Dest.GetName(),
+		DestPath:   m.DestPath,
+		Size:       m.Metadata.Size,
+		Checksum:   checksum,
+		Metadata:   journal.NewMetadata(m.Metadata),
+	}
+
+	switch {
+	case m.IsHardlink:
+		e.Kind = journal.KindHardlink
+		e.LinkTo = m.HardlinkTo.DestPath
+		e.Size = 0
+
+	case m.IsSymlink:
+		e.Kind = journal.KindSymlink
+		e.LinkTo = m.SymlinkTo.DestPath
+		e.Size = 0
+
+	case m.Metadata.IsSymlink:
+		e.Kind = journal.KindSymlink
+		e.LinkTo = m.Metadata.SymlinkTo
+		e.Size = 0
+
+	case m.Metadata.IsDir:
+		e.Kind = journal.KindDirectory
+		e.Size = 0
+	}
+
+	return e
+}
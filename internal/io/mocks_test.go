@@ -8,6 +8,8 @@ import (
 	"context"
 	"os"
 
+	"github.com/desertwitch/gover/internal/intent"
+	"github.com/desertwitch/gover/internal/journal"
 	"github.com/desertwitch/gover/internal/schema"
 	mock "github.com/stretchr/testify/mock"
 	"golang.org/x/sys/unix"
@@ -1021,6 +1023,46 @@ func (_m *mock_ioTargetQueue) EXPECT() *mock_ioTargetQueue_Expecter {
 	return &mock_ioTargetQueue_Expecter{mock: &_m.Mock}
 }
 
+// AddBytesCopied provides a mock function for the type mock_ioTargetQueue
+func (_mock *mock_ioTargetQueue) AddBytesCopied(bytes uint64) {
+	_mock.Called(bytes)
+	return
+}
+
+// mock_ioTargetQueue_AddBytesCopied_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddBytesCopied'
+type mock_ioTargetQueue_AddBytesCopied_Call struct {
+	*mock.Call
+}
+
+// AddBytesCopied is a helper method to define mock.On call
+//   - bytes uint64
+func (_e *mock_ioTargetQueue_Expecter) AddBytesCopied(bytes interface{}) *mock_ioTargetQueue_AddBytesCopied_Call {
+	return &mock_ioTargetQueue_AddBytesCopied_Call{Call: _e.mock.On("AddBytesCopied", bytes)}
+}
+
+func (_c *mock_ioTargetQueue_AddBytesCopied_Call) Run(run func(bytes uint64)) *mock_ioTargetQueue_AddBytesCopied_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 uint64
+		if args[0] != nil {
+			arg0 = args[0].(uint64)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_ioTargetQueue_AddBytesCopied_Call) Return() *mock_ioTargetQueue_AddBytesCopied_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *mock_ioTargetQueue_AddBytesCopied_Call) RunAndReturn(run func(bytes uint64)) *mock_ioTargetQueue_AddBytesCopied_Call {
+	_c.Run(run)
+	return _c
+}
+
 // AddBytesTransfered provides a mock function for the type mock_ioTargetQueue
 func (_mock *mock_ioTargetQueue) AddBytesTransfered(bytes uint64) {
 	_mock.Called(bytes)
@@ -1062,7 +1104,7 @@ func (_c *mock_ioTargetQueue_AddBytesTransfered_Call) RunAndReturn(run func(byte
 }
 
 // DequeueAndProcess provides a mock function for the type mock_ioTargetQueue
-func (_mock *mock_ioTargetQueue) DequeueAndProcess(ctx context.Context, processFunc func(*schema.Moveable) int) error {
+func (_mock *mock_ioTargetQueue) DequeueAndProcess(ctx context.Context, processFunc func(*schema.Moveable) (int, error)) error {
 	ret := _mock.Called(ctx, processFunc)
 
 	if len(ret) == 0 {
@@ -1070,7 +1112,7 @@ func (_mock *mock_ioTargetQueue) DequeueAndProcess(ctx context.Context, processF
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, func(*schema.Moveable) int) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(*schema.Moveable) (int, error)) error); ok {
 		r0 = returnFunc(ctx, processFunc)
 	} else {
 		r0 = ret.Error(0)
@@ -1085,20 +1127,20 @@ type mock_ioTargetQueue_DequeueAndProcess_Call struct {
 
 // DequeueAndProcess is a helper method to define mock.On call
 //   - ctx context.Context
-//   - processFunc func(*schema.Moveable) int
+//   - processFunc func(*schema.Moveable) (int, error)
 func (_e *mock_ioTargetQueue_Expecter) DequeueAndProcess(ctx interface{}, processFunc interface{}) *mock_ioTargetQueue_DequeueAndProcess_Call {
 	return &mock_ioTargetQueue_DequeueAndProcess_Call{Call: _e.mock.On("DequeueAndProcess", ctx, processFunc)}
 }
 
-func (_c *mock_ioTargetQueue_DequeueAndProcess_Call) Run(run func(ctx context.Context, processFunc func(*schema.Moveable) int)) *mock_ioTargetQueue_DequeueAndProcess_Call {
+func (_c *mock_ioTargetQueue_DequeueAndProcess_Call) Run(run func(ctx context.Context, processFunc func(*schema.Moveable) (int, error))) *mock_ioTargetQueue_DequeueAndProcess_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 func(*schema.Moveable) int
+		var arg1 func(*schema.Moveable) (int, error)
 		if args[1] != nil {
-			arg1 = args[1].(func(*schema.Moveable) int)
+			arg1 = args[1].(func(*schema.Moveable) (int, error))
 		}
 		run(
 			arg0,
@@ -1113,7 +1155,70 @@ func (_c *mock_ioTargetQueue_DequeueAndProcess_Call) Return(err error) *mock_ioT
 	return _c
 }
 
-func (_c *mock_ioTargetQueue_DequeueAndProcess_Call) RunAndReturn(run func(ctx context.Context, processFunc func(*schema.Moveable) int) error) *mock_ioTargetQueue_DequeueAndProcess_Call {
+func (_c *mock_ioTargetQueue_DequeueAndProcess_Call) RunAndReturn(run func(ctx context.Context, processFunc func(*schema.Moveable) (int, error)) error) *mock_ioTargetQueue_DequeueAndProcess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DequeueAndProcessConc provides a mock function for the type mock_ioTargetQueue
+func (_mock *mock_ioTargetQueue) DequeueAndProcessConc(ctx context.Context, maxWorkers int, processFunc func(*schema.Moveable) (int, error)) error {
+	ret := _mock.Called(ctx, maxWorkers, processFunc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DequeueAndProcessConc")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, func(*schema.Moveable) (int, error)) error); ok {
+		r0 = returnFunc(ctx, maxWorkers, processFunc)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mock_ioTargetQueue_DequeueAndProcessConc_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DequeueAndProcessConc'
+type mock_ioTargetQueue_DequeueAndProcessConc_Call struct {
+	*mock.Call
+}
+
+// DequeueAndProcessConc is a helper method to define mock.On call
+//   - ctx context.Context
+//   - maxWorkers int
+//   - processFunc func(*schema.Moveable) (int, error)
+func (_e *mock_ioTargetQueue_Expecter) DequeueAndProcessConc(ctx interface{}, maxWorkers interface{}, processFunc interface{}) *mock_ioTargetQueue_DequeueAndProcessConc_Call {
+	return &mock_ioTargetQueue_DequeueAndProcessConc_Call{Call: _e.mock.On("DequeueAndProcessConc", ctx, maxWorkers, processFunc)}
+}
+
+func (_c *mock_ioTargetQueue_DequeueAndProcessConc_Call) Run(run func(ctx context.Context, maxWorkers int, processFunc func(*schema.Moveable) (int, error))) *mock_ioTargetQueue_DequeueAndProcessConc_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 func(*schema.Moveable) (int, error)
+		if args[2] != nil {
+			arg2 = args[2].(func(*schema.Moveable) (int, error))
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_ioTargetQueue_DequeueAndProcessConc_Call) Return(err error) *mock_ioTargetQueue_DequeueAndProcessConc_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mock_ioTargetQueue_DequeueAndProcessConc_Call) RunAndReturn(run func(ctx context.Context, maxWorkers int, processFunc func(*schema.Moveable) (int, error)) error) *mock_ioTargetQueue_DequeueAndProcessConc_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1220,6 +1325,321 @@ func (_c *mock_ioTargetQueue_PreProcess_Call) RunAndReturn(run func(p schema.Pip
 	return _c
 }
 
+// newMock_intentProvider creates a new instance of mock_intentProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMock_intentProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mock_intentProvider {
+	mock := &mock_intentProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mock_intentProvider is an autogenerated mock type for the intentProvider type
+type mock_intentProvider struct {
+	mock.Mock
+}
+
+type mock_intentProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mock_intentProvider) EXPECT() *mock_intentProvider_Expecter {
+	return &mock_intentProvider_Expecter{mock: &_m.Mock}
+}
+
+// Done provides a mock function for the type mock_intentProvider
+func (_mock *mock_intentProvider) Done(destPath string) error {
+	ret := _mock.Called(destPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Done")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(destPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mock_intentProvider_Done_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Done'
+type mock_intentProvider_Done_Call struct {
+	*mock.Call
+}
+
+// Done is a helper method to define mock.On call
+//   - destPath string
+func (_e *mock_intentProvider_Expecter) Done(destPath interface{}) *mock_intentProvider_Done_Call {
+	return &mock_intentProvider_Done_Call{Call: _e.mock.On("Done", destPath)}
+}
+
+func (_c *mock_intentProvider_Done_Call) Run(run func(destPath string)) *mock_intentProvider_Done_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_intentProvider_Done_Call) Return(err error) *mock_intentProvider_Done_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mock_intentProvider_Done_Call) RunAndReturn(run func(destPath string) error) *mock_intentProvider_Done_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Intend provides a mock function for the type mock_intentProvider
+func (_mock *mock_intentProvider) Intend(r *intent.Record) error {
+	ret := _mock.Called(r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Intend")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*intent.Record) error); ok {
+		r0 = returnFunc(r)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mock_intentProvider_Intend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Intend'
+type mock_intentProvider_Intend_Call struct {
+	*mock.Call
+}
+
+// Intend is a helper method to define mock.On call
+//   - r *intent.Record
+func (_e *mock_intentProvider_Expecter) Intend(r interface{}) *mock_intentProvider_Intend_Call {
+	return &mock_intentProvider_Intend_Call{Call: _e.mock.On("Intend", r)}
+}
+
+func (_c *mock_intentProvider_Intend_Call) Run(run func(r *intent.Record)) *mock_intentProvider_Intend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *intent.Record
+		if args[0] != nil {
+			arg0 = args[0].(*intent.Record)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_intentProvider_Intend_Call) Return(err error) *mock_intentProvider_Intend_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mock_intentProvider_Intend_Call) RunAndReturn(run func(r *intent.Record) error) *mock_intentProvider_Intend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMock_journalProvider creates a new instance of mock_journalProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMock_journalProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mock_journalProvider {
+	mock := &mock_journalProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mock_journalProvider is an autogenerated mock type for the journalProvider type
+type mock_journalProvider struct {
+	mock.Mock
+}
+
+type mock_journalProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mock_journalProvider) EXPECT() *mock_journalProvider_Expecter {
+	return &mock_journalProvider_Expecter{mock: &_m.Mock}
+}
+
+// Write provides a mock function for the type mock_journalProvider
+func (_mock *mock_journalProvider) Write(entries ...*journal.Entry) error {
+	// *journal.Entry
+	_va := make([]interface{}, len(entries))
+	for _i := range entries {
+		_va[_i] = entries[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Write")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(...*journal.Entry) error); ok {
+		r0 = returnFunc(entries...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mock_journalProvider_Write_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Write'
+type mock_journalProvider_Write_Call struct {
+	*mock.Call
+}
+
+// Write is a helper method to define mock.On call
+//   - entries ...*journal.Entry
+func (_e *mock_journalProvider_Expecter) Write(entries ...interface{}) *mock_journalProvider_Write_Call {
+	return &mock_journalProvider_Write_Call{Call: _e.mock.On("Write",
+		append([]interface{}{}, entries...)...)}
+}
+
+func (_c *mock_journalProvider_Write_Call) Run(run func(entries ...*journal.Entry)) *mock_journalProvider_Write_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []*journal.Entry
+		variadicArgs := make([]*journal.Entry, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(*journal.Entry)
+			}
+		}
+		arg0 = variadicArgs
+		run(
+			arg0...,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_journalProvider_Write_Call) Return(err error) *mock_journalProvider_Write_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mock_journalProvider_Write_Call) RunAndReturn(run func(entries ...*journal.Entry) error) *mock_journalProvider_Write_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMock_throttleProvider creates a new instance of mock_throttleProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMock_throttleProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mock_throttleProvider {
+	mock := &mock_throttleProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mock_throttleProvider is an autogenerated mock type for the throttleProvider type
+type mock_throttleProvider struct {
+	mock.Mock
+}
+
+type mock_throttleProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mock_throttleProvider) EXPECT() *mock_throttleProvider_Expecter {
+	return &mock_throttleProvider_Expecter{mock: &_m.Mock}
+}
+
+// Wait provides a mock function for the type mock_throttleProvider
+func (_mock *mock_throttleProvider) Wait(ctx context.Context, source string, target string, n int) error {
+	ret := _mock.Called(ctx, source, target, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Wait")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = returnFunc(ctx, source, target, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mock_throttleProvider_Wait_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Wait'
+type mock_throttleProvider_Wait_Call struct {
+	*mock.Call
+}
+
+// Wait is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - target string
+//   - n int
+func (_e *mock_throttleProvider_Expecter) Wait(ctx interface{}, source interface{}, target interface{}, n interface{}) *mock_throttleProvider_Wait_Call {
+	return &mock_throttleProvider_Wait_Call{Call: _e.mock.On("Wait", ctx, source, target, n)}
+}
+
+func (_c *mock_throttleProvider_Wait_Call) Run(run func(ctx context.Context, source string, target string, n int)) *mock_throttleProvider_Wait_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mock_throttleProvider_Wait_Call) Return(err error) *mock_throttleProvider_Wait_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mock_throttleProvider_Wait_Call) RunAndReturn(run func(ctx context.Context, source string, target string, n int) error) *mock_throttleProvider_Wait_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMock_fsElement creates a new instance of mock_fsElement. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMock_fsElement(t interface {
@@ -5,14 +5,16 @@ import (
 )
 
 // ioReport tracks both all creations and encountered directories during IO
-// operations.
+// operations, as well as the directories created for failed operations.
 type ioReport struct {
 	AnyCreated       []fsElement
 	DirsCreated      []*schema.Directory
+	DirsFailed       []*schema.Directory
 	DirsWalked       []*schema.Directory
 	MoveablesCreated []*schema.Moveable
 	SymlinksCreated  []*schema.Moveable
 	HardlinksCreated []*schema.Moveable
+	Checksums        map[*schema.Moveable]string
 }
 
 // mergeIOReports merges a source [ioReport] into a target [ioReport].
@@ -23,10 +25,18 @@ func mergeIOReports(target, source *ioReport) {
 
 	target.AnyCreated = append(target.AnyCreated, source.AnyCreated...)
 	target.DirsCreated = append(target.DirsCreated, source.DirsCreated...)
+	target.DirsFailed = append(target.DirsFailed, source.DirsFailed...)
 	target.DirsWalked = append(target.DirsWalked, source.DirsWalked...)
 	target.HardlinksCreated = append(target.HardlinksCreated, source.HardlinksCreated...)
 	target.MoveablesCreated = append(target.MoveablesCreated, source.MoveablesCreated...)
 	target.SymlinksCreated = append(target.SymlinksCreated, source.SymlinksCreated...)
+
+	for m, checksum := range source.Checksums {
+		if target.Checksums == nil {
+			target.Checksums = make(map[*schema.Moveable]string)
+		}
+		target.Checksums[m] = checksum
+	}
 }
 
 // addToIOReport adds a [schema.Moveable] to an [ioReport].
@@ -0,0 +1,16 @@
+package journal
+
+import "errors"
+
+var (
+	// ErrJournalClosed occurs when entries are written to a [Writer] that has
+	// already been closed.
+	ErrJournalClosed = errors.New("journal is closed")
+
+	// ErrRunNotFound occurs when a journal holds no entries of a given run.
+	ErrRunNotFound = errors.New("run not found in journal")
+
+	// ErrDestChanged occurs when the destination of an [Entry] has changed (or
+	// vanished) since its run, so that its operation can no longer be undone.
+	ErrDestChanged = errors.New("destination changed since the run")
+)
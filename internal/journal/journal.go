@@ -0,0 +1,206 @@
+// Package journal implements an append-only journal (as JSON Lines) of every
+// completed IO operation, that is every file, directory, hard- and symlink that
+// was created (moved) on a target [schema.Storage] or removed from a source
+// [schema.Storage], together with the run the operation belongs to.
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// OpMove is the operation of an element that was created on its target
+	// and removed from its source.
+	OpMove = "move"
+
+	// OpCreate is the operation of a directory that was created on a target
+	// (as part of the directory structure of a moved element).
+	OpCreate = "create"
+
+	// OpRemove is the operation of a directory that was removed from a source
+	// (once it was left empty by moving its elements).
+	OpRemove = "remove"
+
+	// KindFile is the kind of a regular file.
+	KindFile = "file"
+
+	// KindDirectory is the kind of a directory.
+	KindDirectory = "directory"
+
+	// KindHardlink is the kind of a hardlink.
+	KindHardlink = "hardlink"
+
+	// KindSymlink is the kind of a symlink.
+	KindSymlink = "symlink"
+
+	// runIDRandomBytes is the amount of random bytes suffixed to a run ID.
+	runIDRandomBytes = 3
+
+	// filePerms are the permissions a journal file is created with.
+	filePerms = 0o644
+)
+
+// Entry is a single completed operation, as one line of the journal. The
+// [schema.Share] and [schema.Storage] are referenced by their names.
+type Entry struct {
+	RunID      string    `json:"runId"`
+	Time       time.Time `json:"time"`
+	Op         string    `json:"op"`
+	Kind       string    `json:"kind"`
+	Share      string    `json:"share,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	SourcePath string    `json:"sourcePath,omitempty"`
+	Dest       string    `json:"dest,omitempty"`
+	DestPath   string    `json:"destPath,omitempty"`
+	LinkTo     string    `json:"linkTo,omitempty"`
+	Size       uint64    `json:"size"`
+	Checksum   string    `json:"checksum,omitempty"`
+	Metadata   *Metadata `json:"metadata,omitempty"`
+}
+
+// Metadata describes the [schema.Metadata] of an [Entry]. The timestamps are
+// stored as nanoseconds since the Unix epoch.
+type Metadata struct {
+	Perms      uint32 `json:"perms"`
+	UID        uint32 `json:"uid"`
+	GID        uint32 `json:"gid"`
+	AccessedAt int64  `json:"accessedAt"`
+	ModifiedAt int64  `json:"modifiedAt"`
+}
+
+// NewMetadata returns a pointer to new [Metadata] from [schema.Metadata], or
+// nil if no [schema.Metadata] is given.
+func NewMetadata(m *schema.Metadata) *Metadata {
+	if m == nil {
+		return nil
+	}
+
+	return &Metadata{
+		Perms:      m.Perms,
+		UID:        m.UID,
+		GID:        m.GID,
+		AccessedAt: m.AccessedAt.Nano(),
+		ModifiedAt: m.ModifiedAt.Nano(),
+	}
+}
+
+// NewRunID returns a new identifier for a run, made up of its start time and a
+// random suffix (e.g. "20060102-150405-a1b2c3").
+func NewRunID() string {
+	suffix := make([]byte, runIDRandomBytes)
+	_, _ = rand.Read(suffix)
+
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Writer appends [Entry] of one run to a journal file. It is safe for
+// concurrent use.
+type Writer struct {
+	sync.Mutex
+
+	// The identifier of the run all [Entry] are written for.
+	runID string
+
+	// The journal file (nil if the [Writer] discards all [Entry]).
+	file *os.File
+
+	// Whether the [Writer] has been closed.
+	closed bool
+}
+
+// Open returns a pointer to a new [Writer] appending to the journal file at the
+// given path (creating it if needed), for the run of the given identifier. An
+// empty path returns a [Writer] discarding all [Entry].
+func Open(path string, runID string) (*Writer, error) {
+	w := &Writer{
+		runID: runID,
+	}
+
+	if path == "" {
+		return w, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerms)
+	if err != nil {
+		return nil, fmt.Errorf("(journal) failed to open: %w", err)
+	}
+	w.file = f
+
+	return w, nil
+}
+
+// RunID returns the identifier of the run the [Writer] writes [Entry] for.
+func (w *Writer) RunID() string {
+	return w.runID
+}
+
+// Write appends [Entry] (as one line each) to the journal file and syncs it to
+// the disk, setting the run identifier and (if not set) the time of each.
+func (w *Writer) Write(entries ...*Entry) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("(journal) %w", ErrJournalClosed)
+	}
+
+	if w.file == nil || len(entries) == 0 {
+		return nil
+	}
+
+	var lines []byte
+
+	for _, e := range entries {
+		e.RunID = w.runID
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("(journal) failed to marshal: %w", err)
+		}
+
+		lines = append(lines, line...)
+		lines = append(lines, '\n')
+	}
+
+	if _, err := w.file.Write(lines); err != nil {
+		return fmt.Errorf("(journal) failed to write: %w", err)
+	}
+
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("(journal) failed to sync: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the journal file, after which no more [Entry] can be written.
+func (w *Writer) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.file == nil {
+		return nil
+	}
+
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("(journal) failed to close: %w", err)
+	}
+
+	return nil
+}
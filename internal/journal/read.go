@@ -0,0 +1,107 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/schema"
+	"golang.org/x/sys/unix"
+)
+
+// maxLineSize is the maximum size of a single line of a journal.
+const maxLineSize = 1 << 20
+
+// Read reads all [Entry] of the run of the given identifier from a journal (in
+// order of the journal). A malformed (or torn) line is skipped.
+func Read(r io.Reader, runID string) ([]*Entry, error) {
+	var entries []*Entry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+
+	for scanner.Scan() {
+		e := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			slog.Warn("Skipped journal entry: malformed line",
+				"err", err,
+			)
+
+			continue
+		}
+
+		if e.RunID == runID {
+			entries = append(entries, e)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("(journal) failed to read: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("(journal) %w: %s", ErrRunNotFound, runID)
+	}
+
+	return entries, nil
+}
+
+// ToSchema returns a pointer to new [schema.Metadata] from the [Metadata], or
+// nil if the [Metadata] is nil.
+func (m *Metadata) ToSchema() *schema.Metadata {
+	if m == nil {
+		return nil
+	}
+
+	return &schema.Metadata{
+		Perms:      m.Perms,
+		UID:        m.UID,
+		GID:        m.GID,
+		AccessedAt: unix.NsecToTimespec(m.AccessedAt),
+		ModifiedAt: unix.NsecToTimespec(m.ModifiedAt),
+	}
+}
+
+// CheckChanged compares the current [schema.Metadata] of the destination of a
+// moved [Entry] with the one that was recorded, returning an [ErrDestChanged]
+// if they do not match. The contents of a file are not compared here, but by
+// their checksum.
+func CheckChanged(e *Entry, current *schema.Metadata) error {
+	if e.Metadata == nil || current == nil {
+		return fmt.Errorf("(journal-changed) %w: no metadata", ErrDestChanged)
+	}
+
+	isDir := e.Kind == KindDirectory
+	isSymlink := e.Kind == KindSymlink
+
+	if isDir != current.IsDir || isSymlink != current.IsSymlink {
+		return fmt.Errorf("(journal-changed) %w: file type", ErrDestChanged)
+	}
+
+	// Only the size of a file is recorded (not that of its hard- and symlinks).
+	if e.Kind == KindFile && e.Size != current.Size {
+		return fmt.Errorf("(journal-changed) %w: size %d != %d", ErrDestChanged, e.Size, current.Size)
+	}
+
+	// A directory mtime changes with its contents, while a symlink is recreated
+	// (with the time of its move) and keeps no recorded mtime.
+	if !isDir && !isSymlink && e.Metadata.ModifiedAt != current.ModifiedAt.Nano() {
+		return fmt.Errorf("(journal-changed) %w: mtime %d != %d", ErrDestChanged, e.Metadata.ModifiedAt, current.ModifiedAt.Nano())
+	}
+
+	if e.Metadata.Perms != current.Perms {
+		return fmt.Errorf("(journal-changed) %w: perms %o != %o", ErrDestChanged, e.Metadata.Perms, current.Perms)
+	}
+
+	if e.Metadata.UID != current.UID || e.Metadata.GID != current.GID {
+		return fmt.Errorf("(journal-changed) %w: owner %d:%d != %d:%d", ErrDestChanged, e.Metadata.UID, e.Metadata.GID, current.UID, current.GID)
+	}
+
+	if isSymlink && e.LinkTo != current.SymlinkTo {
+		return fmt.Errorf("(journal-changed) %w: symlink target %s != %s", ErrDestChanged, e.LinkTo, current.SymlinkTo)
+	}
+
+	return nil
+}
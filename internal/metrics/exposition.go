@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// typeCounter is the exposition type of a monotonically increasing metric.
+	typeCounter = "counter"
+
+	// typeGauge is the exposition type of an arbitrarily changing metric.
+	typeGauge = "gauge"
+)
+
+// sample is a single value of a [family] with its labels.
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// family is a metric family, consisting of the same-named [sample].
+type family struct {
+	name    string
+	help    string
+	typ     string
+	samples []sample
+}
+
+// add adds a new [sample] to the [family] with labels given as key/value
+// pairs.
+func (f *family) add(value float64, labelPairs ...string) {
+	labels := make(map[string]string, len(labelPairs)/2) //nolint:mnd
+
+	for i := 0; i+1 < len(labelPairs); i += 2 {
+		labels[labelPairs[i]] = labelPairs[i+1]
+	}
+
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// write writes the [family] in the Prometheus text exposition format.
+func (f *family) write(w io.Writer) error {
+	if len(f.samples) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ); err != nil {
+		return fmt.Errorf("(metrics-write) %w", err)
+	}
+
+	for _, s := range f.samples {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(s.labels), formatValue(s.value)); err != nil {
+			return fmt.Errorf("(metrics-write) %w", err)
+		}
+	}
+
+	return nil
+}
+
+// formatLabels returns the exposition representation of a map of labels,
+// sorted by the label names.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"=\""+escapeLabelValue(labels[name])+"\"")
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabelValue escapes a label value for the exposition format.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatValue returns the exposition representation of a sample value.
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
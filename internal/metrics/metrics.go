@@ -0,0 +1,353 @@
+// Package metrics implements the exposition of the application's metrics in
+// the Prometheus text format, either served over HTTP or written to a file for
+// the node_exporter's textfile collector. The metrics are built from the
+// [queue.Manager], the disk usage statistics and the peak memory usage.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// readHeaderTimeout is the timeout for reading a request's headers.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout is the timeout for gracefully shutting down the server.
+	shutdownTimeout = 5 * time.Second
+
+	// contentType is the content type of the text exposition format.
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// textfilePerms are the permissions of a written textfile.
+	textfilePerms = 0o644
+)
+
+// diskStatProvider defines methods needed for disk usage statistics.
+type diskStatProvider interface {
+	GetDiskUsage(s schema.Storage) (filesystem.DiskStats, error)
+}
+
+// memoryProvider defines methods needed for memory usage statistics.
+type memoryProvider interface {
+	GetMaxAlloc() uint64
+}
+
+// Handler is the principal implementation of the metrics services.
+type Handler struct {
+	queueManager    *queue.Manager
+	storages        map[string]schema.Storage
+	diskStatHandler diskStatProvider
+	memoryHandler   memoryProvider
+}
+
+// NewHandler returns a pointer to a new metrics [Handler], exposing the disk
+// usage statistics for the given [schema.Storage].
+func NewHandler(queueManager *queue.Manager, storages map[string]schema.Storage, diskStatHandler diskStatProvider, memoryHandler memoryProvider) *Handler {
+	return &Handler{
+		queueManager:    queueManager,
+		storages:        storages,
+		diskStatHandler: diskStatHandler,
+		memoryHandler:   memoryHandler,
+	}
+}
+
+// Write writes all metrics in the Prometheus text exposition format.
+func (h *Handler) Write(w io.Writer) error {
+	for _, f := range h.collect() {
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP serves all metrics, making the [Handler] a [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+
+	if err := h.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// Serve serves all metrics on a given [net.Listener] until the context is
+// cancelled, at which point the server is gracefully shut down.
+func (h *Handler) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx) //nolint:contextcheck
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("(metrics) failed to serve: %w", err)
+	}
+
+	return nil
+}
+
+// WriteTextfile writes all metrics to a file at the given path. The file is
+// replaced atomically, so that a collector never reads a partial file.
+func (h *Handler) WriteTextfile(path string) error {
+	var buf bytes.Buffer
+
+	if err := h.Write(&buf); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("(metrics-textfile) failed to create: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("(metrics-textfile) failed to write: %w", err)
+	}
+
+	if err := tmp.Chmod(textfilePerms); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("(metrics-textfile) failed to chmod: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("(metrics-textfile) failed to close: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("(metrics-textfile) failed to rename: %w", err)
+	}
+
+	return nil
+}
+
+// RunTextfile writes all metrics to a file at the given path at every
+// interval, until the context is cancelled, at which point the metrics are
+// written a last (final) time.
+func (h *Handler) RunTextfile(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := h.WriteTextfile(path); err != nil {
+				slog.Warn("Failed to write metrics textfile.",
+					"err", err,
+					"path", path,
+				)
+			}
+
+			return
+
+		case <-ticker.C:
+			if err := h.WriteTextfile(path); err != nil {
+				slog.Warn("Failed to write metrics textfile.",
+					"err", err,
+					"path", path,
+				)
+			}
+		}
+	}
+}
+
+// collect returns all metric families for the current state.
+func (h *Handler) collect() []*family {
+	snapshot := progress.Collect(h.queueManager)
+
+	families := []*family{}
+	families = append(families, collectStages(snapshot)...)
+	families = append(families, collectQueues(snapshot)...)
+	families = append(families, h.collectShares())
+	families = append(families, h.collectStorages()...)
+
+	memory := &family{name: "gover_memory_peak_bytes", help: "Peak memory allocation of the application.", typ: typeGauge}
+	if h.memoryHandler != nil {
+		memory.add(float64(h.memoryHandler.GetMaxAlloc()))
+	}
+
+	timestamp := &family{name: "gover_metrics_timestamp_seconds", help: "Time the metrics were collected at.", typ: typeGauge}
+	timestamp.add(float64(snapshot.Time.Unix()))
+
+	return append(families, memory, timestamp)
+}
+
+// collectStages returns the metric families for the stages (managers).
+func collectStages(s *progress.Snapshot) []*family {
+	items := &family{name: "gover_stage_items", help: "Items of a stage by their state.", typ: typeGauge}
+	pct := &family{name: "gover_stage_progress_percent", help: "Progress of a stage in percent.", typ: typeGauge}
+
+	for _, r := range []progress.Record{s.Enumeration, s.Evaluation, s.IO} {
+		addItemStates(items, r, "stage", r.Stage)
+		pct.add(r.ProgressPct, "stage", r.Stage)
+	}
+
+	return []*family{items, pct}
+}
+
+// collectQueues returns the metric families for the queues of all stages.
+func collectQueues(s *progress.Snapshot) []*family {
+	items := &family{name: "gover_queue_items", help: "Items of a queue by their state.", typ: typeGauge}
+	transferred := &family{name: "gover_io_bytes_transferred_total", help: "Bytes transferred to a target storage.", typ: typeCounter}
+
+	for _, r := range s.Queues {
+		addItemStates(items, r, "stage", r.Stage, "queue", r.Queue)
+
+		if r.Stage == progress.StageIO {
+			transferred.add(float64(r.BytesTransferred), "target", r.Queue)
+		}
+	}
+
+	return []*family{items, transferred}
+}
+
+// addItemStates adds a [sample] to a [family] for each item state of a
+// [progress.Record].
+func addItemStates(f *family, r progress.Record, labelPairs ...string) {
+	states := []struct {
+		name  string
+		value int
+	}{
+		{"total", r.TotalItems},
+		{"processed", r.ProcessedItems},
+		{"in_progress", r.InProgressItems},
+		{"success", r.SuccessItems},
+		{"skipped", r.SkippedItems},
+	}
+
+	for _, state := range states {
+		f.add(float64(state.value), append(append([]string{}, labelPairs...), "state", state.name)...)
+	}
+}
+
+// shareKey is the key for counting the items of a [schema.Share] by stage and
+// state.
+type shareKey struct {
+	stage string
+	share string
+	state string
+}
+
+// collectShares returns the metric family for the successful and skipped
+// items per [schema.Share] and stage.
+func (h *Handler) collectShares() *family {
+	items := &family{name: "gover_share_items", help: "Successful and skipped items of a share by stage.", typ: typeGauge}
+	counts := make(map[shareKey]int)
+
+	for _, q := range h.queueManager.EnumerationManager.GetQueues() {
+		countByShare(counts, progress.StageEnumeration, q.GetSuccessful(), q.GetSkipped(), func(t *queue.EnumerationTask) schema.Share {
+			return t.Share
+		})
+	}
+
+	for _, q := range h.queueManager.EvaluationManager.GetQueues() {
+		countByShare(counts, progress.StageEvaluation, q.GetSuccessful(), q.GetSkipped(), func(m *schema.Moveable) schema.Share {
+			return m.Share
+		})
+	}
+
+	for _, q := range h.queueManager.IOManager.GetQueues() {
+		countByShare(counts, progress.StageIO, q.GetSuccessful(), q.GetSkipped(), func(m *schema.Moveable) schema.Share {
+			return m.Share
+		})
+	}
+
+	keys := make([]shareKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].stage != keys[j].stage {
+			return keys[i].stage < keys[j].stage
+		}
+		if keys[i].share != keys[j].share {
+			return keys[i].share < keys[j].share
+		}
+
+		return keys[i].state < keys[j].state
+	})
+
+	for _, key := range keys {
+		items.add(float64(counts[key]), "stage", key.stage, "share", key.share, "state", key.state)
+	}
+
+	return items
+}
+
+// countByShare counts successful and skipped items of a stage by their
+// [schema.Share], as returned by the getShareFunc.
+func countByShare[V any](counts map[shareKey]int, stage string, successful []V, skipped []V, getShareFunc func(V) schema.Share) {
+	for state, items := range map[string][]V{"success": successful, "skipped": skipped} {
+		for _, item := range items {
+			share := getShareFunc(item)
+			if share == nil {
+				continue
+			}
+
+			// Ensure both states are exposed, even if one of them is zero.
+			counts[shareKey{stage: stage, share: share.GetName(), state: "success"}] += 0
+			counts[shareKey{stage: stage, share: share.GetName(), state: "skipped"}] += 0
+
+			counts[shareKey{stage: stage, share: share.GetName(), state: state}]++
+		}
+	}
+}
+
+// collectStorages returns the metric families for the disk usage statistics of
+// all [schema.Storage].
+func (h *Handler) collectStorages() []*family {
+	free := &family{name: "gover_storage_free_bytes", help: "Free space of a storage.", typ: typeGauge}
+	total := &family{name: "gover_storage_total_bytes", help: "Total size of a storage.", typ: typeGauge}
+
+	names := make([]string, 0, len(h.storages))
+	for name := range h.storages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		stats, err := h.diskStatHandler.GetDiskUsage(h.storages[name])
+		if err != nil {
+			continue
+		}
+
+		free.add(float64(stats.FreeSpace), "storage", name)
+		total.add(float64(stats.TotalSize), "storage", name)
+	}
+
+	return []*family{free, total}
+}
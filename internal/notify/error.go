@@ -0,0 +1,9 @@
+package notify
+
+import "errors"
+
+var (
+	// ErrNotifyFailed occurs when the notify script has exited with a non-zero
+	// exit code (or could not be run at all).
+	ErrNotifyFailed = errors.New("notify script failed")
+)
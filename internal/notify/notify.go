@@ -0,0 +1,167 @@
+// Package notify implements routines for sending notifications through the
+// Unraid notification system (by its notify script), both for the summary of a
+// run and immediately for conditions that need attention while still running
+// (hash mismatches, out-of-space conditions and aborted runs).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desertwitch/gover/internal/io"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DefaultScript is the path of the stock Unraid notify script.
+	DefaultScript = "/usr/local/emhttp/webGui/scripts/notify"
+
+	// ImportanceNormal is the importance of a notification for information.
+	ImportanceNormal = "normal"
+
+	// ImportanceWarning is the importance of a notification for a warning.
+	ImportanceWarning = "warning"
+
+	// ImportanceAlert is the importance of a notification for an alert.
+	ImportanceAlert = "alert"
+
+	// event is the event name all notifications are sent with.
+	event = "gover"
+
+	// sendTimeout is the time the notify script may run before it is killed.
+	sendTimeout = 30 * time.Second
+)
+
+// Notification is a single notification to be sent.
+type Notification struct {
+	Subject     string
+	Description string
+	Importance  string
+}
+
+// condition is a condition for which a [Notification] is sent immediately.
+type condition struct {
+	subject string
+	errs    []error
+}
+
+// conditions are all conditions that are notified immediately, as soon as an
+// error matching them is checked.
+var conditions = []condition{
+	{"Hash mismatch", []error{io.ErrHashMismatch}},
+	{"Out of space", []error{io.ErrNotEnoughSpace, unix.ENOSPC}},
+}
+
+// Handler is the principal implementation of the notification services. It
+// sends a [Notification] for the first error of each of its conditions (e.g. a
+// hash mismatch) as soon as such an error is checked. It is safe for concurrent
+// use.
+type Handler struct {
+	sync.Mutex
+
+	// The path of the notify script.
+	script string
+
+	// The subjects of the conditions (or notifications) already sent once.
+	sent map[string]struct{}
+
+	// The sending notifications that are still running.
+	running sync.WaitGroup
+}
+
+// NewHandler returns a pointer to a new notification [Handler], sending its
+// notifications with the notify script at the given path.
+func NewHandler(script string) *Handler {
+	return &Handler{
+		script: script,
+		sent:   make(map[string]struct{}),
+	}
+}
+
+// Send sends a [Notification] with the notify script.
+func (h *Handler) Send(ctx context.Context, n Notification) error {
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(sendCtx, h.script,
+		"-e", event,
+		"-s", n.Subject,
+		"-d", n.Description,
+		"-i", n.Importance,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("(notify) %w: %w: %s", ErrNotifyFailed, err, msg)
+		}
+
+		return fmt.Errorf("(notify) %w: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
+
+// SendOnce sends a [Notification] in the background, unless another one with
+// the same subject was already sent by the [Handler] before. A failure is only
+// logged.
+func (h *Handler) SendOnce(n Notification) {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, sent := h.sent[n.Subject]; sent {
+		return
+	}
+	h.sent[n.Subject] = struct{}{}
+
+	h.running.Add(1)
+	go func() {
+		defer h.running.Done()
+
+		if err := h.Send(context.Background(), n); err != nil {
+			slog.Error("Failed to send notification.",
+				"err", err,
+				"subject", n.Subject,
+			)
+		}
+	}()
+}
+
+// Wait waits for all notifications sent in the background to finish.
+func (h *Handler) Wait() {
+	h.running.Wait()
+}
+
+// Check sends a [Notification] for the error of a job (in the background), if
+// it matches one of the conditions not yet notified.
+func (h *Handler) Check(job string, err error) {
+	for _, c := range conditions {
+		for _, target := range c.errs {
+			if errors.Is(err, target) {
+				h.SendOnce(Notification{
+					Subject:     c.subject,
+					Description: describe(job, err),
+					Importance:  ImportanceAlert,
+				})
+
+				return
+			}
+		}
+	}
+}
+
+// describe returns the description of a [Notification] for the error of a job.
+func describe(job string, err error) string {
+	description := err.Error()
+	if job != "" {
+		description += " (" + job + ")"
+	}
+
+	return description + " - further occurrences are included in the summary."
+}
@@ -31,37 +31,26 @@ func NewHandler(osHandler osProvider) *Handler {
 
 // EstablishPath is the principal pathing function that ensures that valid
 // destination paths are constructed for a [schema.Moveable]'s set (previously
-// allocated) destination [schema.Storage].
-func (f *Handler) EstablishPath(m *schema.Moveable) bool {
+// allocated) destination [schema.Storage]. It returns the error of the first
+// element (or subelement) a path could not be established for.
+func (f *Handler) EstablishPath(m *schema.Moveable) error {
 	if err := f.establishElementPath(m); err != nil {
-		return false
+		return err
 	}
 
-	hardLinkFailure := false
 	for _, h := range m.Hardlinks {
 		if err := f.establishSubElementPath(h, m); err != nil {
-			hardLinkFailure = true
-
-			break
+			return err
 		}
 	}
-	if hardLinkFailure {
-		return false
-	}
 
-	symlinkFailure := false
 	for _, s := range m.Symlinks {
 		if err := f.establishSubElementPath(s, m); err != nil {
-			symlinkFailure = true
-
-			break
+			return err
 		}
 	}
-	if symlinkFailure {
-		return false
-	}
 
-	return true
+	return nil
 }
 
 // establishElementPath constructs the destination paths for a "parent"
@@ -82,6 +71,7 @@ func (f *Handler) establishElementPath(elem *schema.Moveable) error {
 	// A directory is allowed to exist, that gets handled later in IO.
 	if !elem.Metadata.IsDir && existsPath != "" {
 		slog.Warn("Skipped job: destination path already exists",
+			"err", ErrPathExistsOnDest,
 			"path", existsPath,
 			"dst", elem.Dest.GetName(),
 			"job", elem.SourcePath,
@@ -122,6 +112,7 @@ func (f *Handler) establishSubElementPath(subelem *schema.Moveable, elem *schema
 	}
 	if existsPath != "" {
 		slog.Warn("Skipped job: destination path already exists for subjob",
+			"err", ErrPathExistsOnDest,
 			"path", existsPath,
 			"dst", subelem.Dest.GetName(),
 			"subjob", subelem.SourcePath,
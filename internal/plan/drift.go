@@ -0,0 +1,43 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// CheckDrift compares the recorded [schema.Metadata] of a planned element with
+// its current [schema.Metadata] on the filesystem. An [ErrSourceChanged] is
+// returned if the inode, size, modification time or permissions differ.
+func CheckDrift(recorded *schema.Metadata, current *schema.Metadata) error {
+	if recorded == nil || current == nil {
+		return fmt.Errorf("(plan-drift) %w", ErrNoMetadata)
+	}
+
+	if recorded.Inode != current.Inode {
+		return fmt.Errorf("(plan-drift) %w: inode %d != %d", ErrSourceChanged, recorded.Inode, current.Inode)
+	}
+
+	if recorded.IsDir != current.IsDir || recorded.IsSymlink != current.IsSymlink {
+		return fmt.Errorf("(plan-drift) %w: file type", ErrSourceChanged)
+	}
+
+	// A directory size is filesystem-specific and not of interest here.
+	if !recorded.IsDir && recorded.Size != current.Size {
+		return fmt.Errorf("(plan-drift) %w: size %d != %d", ErrSourceChanged, recorded.Size, current.Size)
+	}
+
+	if recorded.ModifiedAt != current.ModifiedAt {
+		return fmt.Errorf("(plan-drift) %w: mtime %d != %d", ErrSourceChanged, recorded.ModifiedAt.Nano(), current.ModifiedAt.Nano())
+	}
+
+	if recorded.Perms != current.Perms {
+		return fmt.Errorf("(plan-drift) %w: perms %o != %o", ErrSourceChanged, recorded.Perms, current.Perms)
+	}
+
+	if recorded.SymlinkTo != current.SymlinkTo {
+		return fmt.Errorf("(plan-drift) %w: symlink target %s != %s", ErrSourceChanged, recorded.SymlinkTo, current.SymlinkTo)
+	}
+
+	return nil
+}
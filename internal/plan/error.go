@@ -0,0 +1,21 @@
+package plan
+
+import "errors"
+
+var (
+	// ErrUnsupportedVersion occurs when a persisted [Plan] was created with an
+	// unsupported [FormatVersion].
+	ErrUnsupportedVersion = errors.New("unsupported plan version")
+
+	// ErrNoMetadata occurs when a planned element has no recorded metadata to
+	// compare against.
+	ErrNoMetadata = errors.New("no recorded metadata")
+
+	// ErrSourceChanged occurs when a planned element has changed on the
+	// filesystem since the [Plan] was created.
+	ErrSourceChanged = errors.New("source changed since planning")
+
+	// ErrSourceVanished occurs when a planned element no longer exists on the
+	// filesystem since the [Plan] was created.
+	ErrSourceVanished = errors.New("source vanished since planning")
+)
@@ -0,0 +1,104 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// tableMinWidth is the minimal cell width of the human-readable table.
+	tableMinWidth = 0
+
+	// tableTabWidth is the tab width of the human-readable table.
+	tableTabWidth = 8
+
+	// tablePadding is the cell padding of the human-readable table.
+	tablePadding = 2
+)
+
+// ReadJSON reads a [Plan] that was previously written using [Plan.WriteJSON].
+// An [ErrUnsupportedVersion] is returned for an incompatible [FormatVersion].
+func ReadJSON(r io.Reader) (*Plan, error) {
+	p := &Plan{}
+
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, fmt.Errorf("(plan-json) failed to decode: %w", err)
+	}
+
+	if p.Version != FormatVersion {
+		return nil, fmt.Errorf("(plan-json) %w: %d", ErrUnsupportedVersion, p.Version)
+	}
+
+	return p, nil
+}
+
+// WriteJSON writes the [Plan] as machine-readable (indented) JSON.
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(p); err != nil {
+		return fmt.Errorf("(plan-json) failed to encode: %w", err)
+	}
+
+	return nil
+}
+
+// WriteTable writes the [Plan] as a human-readable table, grouped by the
+// planned target storages.
+func (p *Plan) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, tableMinWidth, tableTabWidth, tablePadding, ' ', 0)
+
+	for _, t := range p.Targets {
+		fmt.Fprintf(tw, "TARGET: %s (%s) - %d items, %s\n", t.Name, t.FSPath, t.TotalItems, humanize.IBytes(t.TotalSize))
+		fmt.Fprintln(tw, "SHARE\tSOURCE\tSOURCE PATH\tDEST PATH\tSIZE\tLINKS")
+
+		for _, e := range t.Entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.Share, e.Source, e.SourcePath, e.DestPath, formatSize(e), formatLinks(e))
+
+			for _, h := range e.Hardlinks {
+				fmt.Fprintf(tw, "\t\t  %s\t  %s\t\thardlink\n", h.SourcePath, h.DestPath)
+			}
+
+			for _, s := range e.Symlinks {
+				fmt.Fprintf(tw, "\t\t  %s\t  %s\t\tsymlink\n", s.SourcePath, s.DestPath)
+			}
+		}
+
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintf(tw, "TOTAL: %d targets, %d items, %s\n", len(p.Targets), p.TotalItems(), humanize.IBytes(p.TotalSize()))
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("(plan-table) failed to flush: %w", err)
+	}
+
+	return nil
+}
+
+// formatSize returns the human-readable size column for an [Entry].
+func formatSize(e *Entry) string {
+	switch {
+	case e.Metadata.IsDir:
+		return "<dir>"
+	case e.Metadata.IsSymlink:
+		return "<symlink>"
+	default:
+		return humanize.IBytes(e.Metadata.Size)
+	}
+}
+
+// formatLinks returns the human-readable links column for an [Entry].
+func formatLinks(e *Entry) string {
+	if len(e.Hardlinks) == 0 && len(e.Symlinks) == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%dH/%dS", len(e.Hardlinks), len(e.Symlinks))
+}
@@ -0,0 +1,211 @@
+// Package plan implements structures and routines for describing the outcome of
+// an evaluation as a move plan. A plan lists for each target [schema.Storage]
+// all [schema.Moveable] that would be moved there, without any IO happening.
+//
+// A plan can be persisted (as JSON) and later be turned back into
+// [schema.Moveable], so that exactly the planned moves can be applied at a
+// later point in time (after detecting any drift on the filesystem).
+package plan
+
+import (
+	"sort"
+	"time"
+
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// FormatVersion is the version of the persisted [Plan] format. It is
+	// increased whenever the format changes incompatibly.
+	FormatVersion = 1
+)
+
+// Plan is the principal structure describing all planned moves, grouped by
+// their respective target [schema.Storage]. It is meant to be passed by
+// reference (pointer).
+type Plan struct {
+	// Version is the [FormatVersion] the plan was created with.
+	Version int `json:"version"`
+
+	// CreatedAt is the time the plan was created at.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Targets are all planned target storages, sorted by their name.
+	Targets []*Target `json:"targets"`
+}
+
+// Target holds all planned moves for one specific target [schema.Storage].
+type Target struct {
+	// Name is the name of the target storage.
+	Name string `json:"name"`
+
+	// FSPath is the absolute filesystem path of the target storage.
+	FSPath string `json:"fsPath"`
+
+	// TotalItems is the amount of planned [Entry] for the target storage.
+	TotalItems int `json:"totalItems"`
+
+	// TotalSize is the sum of all [Entry] sizes for the target storage.
+	TotalSize uint64 `json:"totalSize"`
+
+	// Entries are the planned moves for the target storage, in order.
+	Entries []*Entry `json:"entries"`
+}
+
+// Entry describes the planned move of a single "parent" [schema.Moveable]. The
+// [schema.Share] and [schema.Storage] are referenced by their names.
+type Entry struct {
+	// Share is the name of the share the entry belongs to.
+	Share string `json:"share"`
+
+	// Source is the name of the source storage.
+	Source string `json:"source"`
+
+	// SourcePath is the absolute path on the source storage.
+	SourcePath string `json:"sourcePath"`
+
+	// Dest is the name of the (allocated) target storage.
+	Dest string `json:"dest"`
+
+	// DestPath is the absolute path on the target storage.
+	DestPath string `json:"destPath"`
+
+	// Metadata is the metadata of the source at the time of the planning.
+	Metadata *Metadata `json:"metadata"`
+
+	// Directories are the parent directories to be recreated on the target.
+	Directories []*Directory `json:"directories,omitempty"`
+
+	// Hardlinks are the hardlinks to the entry, moved along with it.
+	Hardlinks []*Link `json:"hardlinks,omitempty"`
+
+	// Symlinks are the symlinks to the entry, moved along with it.
+	Symlinks []*Link `json:"symlinks,omitempty"`
+}
+
+// Link describes a planned hard- or symlink subelement of an [Entry]. It
+// shares the [schema.Share] and [schema.Storage] of its [Entry].
+type Link struct {
+	// SourcePath is the absolute path on the source storage.
+	SourcePath string `json:"sourcePath"`
+
+	// DestPath is the absolute path on the target storage.
+	DestPath string `json:"destPath"`
+
+	// Metadata is the metadata of the link at the time of the planning.
+	Metadata *Metadata `json:"metadata"`
+
+	// Directories are the parent directories to be recreated on the target.
+	Directories []*Directory `json:"directories,omitempty"`
+}
+
+// Directory describes a [schema.Directory] of an [Entry] or [Link]. The
+// directories are stored as a slice, ordered from the shallowest (root) to the
+// deepest directory of the chain.
+type Directory struct {
+	// SourcePath is the absolute path on the source storage.
+	SourcePath string `json:"sourcePath"`
+
+	// DestPath is the absolute path on the target storage.
+	DestPath string `json:"destPath"`
+
+	// Metadata is the metadata of the directory at the time of the planning.
+	Metadata *Metadata `json:"metadata"`
+}
+
+// Metadata describes the [schema.Metadata] of an [Entry], [Link] or
+// [Directory]. The timestamps are stored as nanoseconds since the Unix epoch.
+type Metadata struct {
+	// Inode is the inode number (for detecting a replaced source).
+	Inode uint64 `json:"inode"`
+
+	// Perms are the permission bits.
+	Perms uint32 `json:"perms"`
+
+	// UID is the identifier of the owning user.
+	UID uint32 `json:"uid"`
+
+	// GID is the identifier of the owning group.
+	GID uint32 `json:"gid"`
+
+	// AccessedAt is the time of the last access.
+	AccessedAt int64 `json:"accessedAt"`
+
+	// ModifiedAt is the time of the last modification.
+	ModifiedAt int64 `json:"modifiedAt"`
+
+	// ChangedAt is the time of the last status change.
+	ChangedAt int64 `json:"changedAt,omitempty"`
+
+	// Size is the size in bytes.
+	Size uint64 `json:"size"`
+
+	// IsDir is whether it is a directory.
+	IsDir bool `json:"isDir"`
+
+	// IsSymlink is whether it is a symlink.
+	IsSymlink bool `json:"isSymlink"`
+
+	// SymlinkTo is the target of a symlink.
+	SymlinkTo string `json:"symlinkTo,omitempty"`
+}
+
+// New returns a pointer to a new [Plan], built from a map of target
+// [schema.Storage] and the [schema.Moveable] that are due to be moved there.
+func New(targets map[schema.Storage][]*schema.Moveable) *Plan {
+	p := &Plan{
+		Version:   FormatVersion,
+		CreatedAt: time.Now(),
+		Targets:   []*Target{},
+	}
+
+	for storage, moveables := range targets {
+		if len(moveables) == 0 {
+			continue
+		}
+
+		target := &Target{
+			Name:    storage.GetName(),
+			FSPath:  storage.GetFSPath(),
+			Entries: make([]*Entry, 0, len(moveables)),
+		}
+
+		for _, m := range moveables {
+			entry := newEntry(m)
+
+			target.TotalItems++
+			target.TotalSize += entry.Metadata.Size
+			target.Entries = append(target.Entries, entry)
+		}
+
+		p.Targets = append(p.Targets, target)
+	}
+
+	sort.Slice(p.Targets, func(i, j int) bool {
+		return p.Targets[i].Name < p.Targets[j].Name
+	})
+
+	return p
+}
+
+// TotalItems returns the amount of planned [Entry] across all [Target].
+func (p *Plan) TotalItems() int {
+	var total int
+
+	for _, t := range p.Targets {
+		total += t.TotalItems
+	}
+
+	return total
+}
+
+// TotalSize returns the sum of all planned [Entry] sizes across all [Target].
+func (p *Plan) TotalSize() uint64 {
+	var total uint64
+
+	for _, t := range p.Targets {
+		total += t.TotalSize
+	}
+
+	return total
+}
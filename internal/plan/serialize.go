@@ -0,0 +1,206 @@
+package plan
+
+import (
+	"log/slog"
+
+	"github.com/desertwitch/gover/internal/schema"
+	"golang.org/x/sys/unix"
+)
+
+// Moveables turns the [Plan] back into [schema.Moveable] (including their
+// subelements and [schema.Directory] chains), resolving the referenced
+// [schema.Share] and [schema.Storage] by their names. Entries referencing a
+// no longer existing [schema.Share] or [schema.Storage] are skipped.
+func (p *Plan) Moveables(shares map[string]schema.Share, storages map[string]schema.Storage) []*schema.Moveable {
+	moveables := []*schema.Moveable{}
+
+	for _, t := range p.Targets {
+		for _, e := range t.Entries {
+			share, shareExists := shares[e.Share]
+			source, sourceExists := storages[e.Source]
+			dest, destExists := storages[e.Dest]
+
+			if !shareExists || !sourceExists || !destExists {
+				slog.Warn("Skipped job: planned share or storage no longer exists",
+					"src", e.Source,
+					"dst", e.Dest,
+					"job", e.SourcePath,
+					"share", e.Share,
+				)
+
+				continue
+			}
+
+			m := &schema.Moveable{
+				Share:      share,
+				Source:     source,
+				SourcePath: e.SourcePath,
+				Dest:       dest,
+				DestPath:   e.DestPath,
+				Metadata:   e.Metadata.toSchema(),
+				RootDir:    toSchemaDirectories(e.Directories),
+			}
+
+			for _, h := range e.Hardlinks {
+				hardlink := h.toSchema(m)
+				hardlink.IsHardlink = true
+				hardlink.HardlinkTo = m
+
+				m.Hardlinks = append(m.Hardlinks, hardlink)
+			}
+
+			for _, s := range e.Symlinks {
+				symlink := s.toSchema(m)
+				symlink.IsSymlink = true
+				symlink.SymlinkTo = m
+
+				m.Symlinks = append(m.Symlinks, symlink)
+			}
+
+			moveables = append(moveables, m)
+		}
+	}
+
+	return moveables
+}
+
+// newEntry returns a pointer to a new [Entry] for a [schema.Moveable].
+func newEntry(m *schema.Moveable) *Entry {
+	entry := &Entry{
+		SourcePath:  m.SourcePath,
+		DestPath:    m.DestPath,
+		Metadata:    newMetadata(m.Metadata),
+		Directories: newDirectories(m.RootDir),
+	}
+
+	if m.Share != nil {
+		entry.Share = m.Share.GetName()
+	}
+
+	if m.Source != nil {
+		entry.Source = m.Source.GetName()
+	}
+
+	if m.Dest != nil {
+		entry.Dest = m.Dest.GetName()
+	}
+
+	for _, h := range m.Hardlinks {
+		entry.Hardlinks = append(entry.Hardlinks, newLink(h))
+	}
+
+	for _, s := range m.Symlinks {
+		entry.Symlinks = append(entry.Symlinks, newLink(s))
+	}
+
+	return entry
+}
+
+// newLink returns a pointer to a new [Link] for a [schema.Moveable]
+// subelement.
+func newLink(m *schema.Moveable) *Link {
+	return &Link{
+		SourcePath:  m.SourcePath,
+		DestPath:    m.DestPath,
+		Metadata:    newMetadata(m.Metadata),
+		Directories: newDirectories(m.RootDir),
+	}
+}
+
+// toSchema returns a pointer to a new [schema.Moveable] subelement for a
+// [Link], inheriting the [schema.Share] and [schema.Storage] of the parent.
+func (l *Link) toSchema(parent *schema.Moveable) *schema.Moveable {
+	return &schema.Moveable{
+		Share:      parent.Share,
+		Source:     parent.Source,
+		SourcePath: l.SourcePath,
+		Dest:       parent.Dest,
+		DestPath:   l.DestPath,
+		Metadata:   l.Metadata.toSchema(),
+		RootDir:    toSchemaDirectories(l.Directories),
+	}
+}
+
+// newDirectories returns a slice of [Directory] for a chain of
+// [schema.Directory], ordered from the given root to the deepest child.
+func newDirectories(root *schema.Directory) []*Directory {
+	var dirs []*Directory
+
+	for dir := root; dir != nil; dir = dir.Child {
+		dirs = append(dirs, &Directory{
+			SourcePath: dir.SourcePath,
+			DestPath:   dir.DestPath,
+			Metadata:   newMetadata(dir.Metadata),
+		})
+	}
+
+	return dirs
+}
+
+// toSchemaDirectories returns the root of a new chain of [schema.Directory]
+// for a slice of [Directory], establishing their parent/child relations.
+func toSchemaDirectories(dirs []*Directory) *schema.Directory {
+	var root, prev *schema.Directory
+
+	for _, d := range dirs {
+		dir := &schema.Directory{
+			SourcePath: d.SourcePath,
+			DestPath:   d.DestPath,
+			Metadata:   d.Metadata.toSchema(),
+		}
+
+		if prev == nil {
+			root = dir
+		} else {
+			prev.Child = dir
+			dir.Parent = prev
+		}
+		prev = dir
+	}
+
+	return root
+}
+
+// newMetadata returns a pointer to a new [Metadata] for a [schema.Metadata].
+// If the given [schema.Metadata] is nil, an empty [Metadata] is returned.
+func newMetadata(m *schema.Metadata) *Metadata {
+	if m == nil {
+		return &Metadata{}
+	}
+
+	return &Metadata{
+		Inode:      m.Inode,
+		Perms:      m.Perms,
+		UID:        m.UID,
+		GID:        m.GID,
+		AccessedAt: m.AccessedAt.Nano(),
+		ModifiedAt: m.ModifiedAt.Nano(),
+		ChangedAt:  m.ChangedAt.Nano(),
+		Size:       m.Size,
+		IsDir:      m.IsDir,
+		IsSymlink:  m.IsSymlink,
+		SymlinkTo:  m.SymlinkTo,
+	}
+}
+
+// toSchema returns a pointer to a new [schema.Metadata] for a [Metadata]. If
+// the [Metadata] is nil, nil is returned.
+func (m *Metadata) toSchema() *schema.Metadata {
+	if m == nil {
+		return nil
+	}
+
+	return &schema.Metadata{
+		Inode:      m.Inode,
+		Perms:      m.Perms,
+		UID:        m.UID,
+		GID:        m.GID,
+		AccessedAt: unix.NsecToTimespec(m.AccessedAt),
+		ModifiedAt: unix.NsecToTimespec(m.ModifiedAt),
+		ChangedAt:  unix.NsecToTimespec(m.ChangedAt),
+		Size:       m.Size,
+		IsDir:      m.IsDir,
+		IsSymlink:  m.IsSymlink,
+		SymlinkTo:  m.SymlinkTo,
+	}
+}
@@ -0,0 +1,8 @@
+package priority
+
+import "errors"
+
+var (
+	// ErrInvalidSettings occurs when the scheduling settings are out of range.
+	ErrInvalidSettings = errors.New("invalid scheduling settings")
+)
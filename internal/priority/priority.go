@@ -0,0 +1,151 @@
+// Package priority implements setting the IO scheduling class and priority
+// (see ioprio_set(2)) and the CPU niceness (see setpriority(2)) of the
+// application. Both are set for every thread of the process, with any threads
+// (and subprocesses) created afterwards inheriting them, as well as explicitly
+// for spawned subprocesses.
+package priority
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ClassNone keeps the IO scheduling class (and priority) unchanged.
+	ClassNone = ""
+
+	// ClassBestEffort is the best-effort IO scheduling class, which is served
+	// according to its priority level.
+	ClassBestEffort = "best-effort"
+
+	// ClassIdle is the idle IO scheduling class, which is only served when no
+	// other IO is pending.
+	ClassIdle = "idle"
+
+	// MinLevel is the highest priority level of the best-effort class.
+	MinLevel = 0
+
+	// MaxLevel is the lowest priority level of the best-effort class.
+	MaxLevel = 7
+
+	// MinNice is the highest CPU priority (niceness).
+	MinNice = -20
+
+	// MaxNice is the lowest CPU priority (niceness).
+	MaxNice = 19
+
+	// ioprioWhoProcess targets a single thread (or process) by its identifier.
+	ioprioWhoProcess = 1
+
+	// ioprioClassShift is the bit shift of the class within an IO priority.
+	ioprioClassShift = 13
+
+	// ioprioClassBE is the kernel's best-effort IO scheduling class.
+	ioprioClassBE = 2
+
+	// ioprioClassIdle is the kernel's idle IO scheduling class.
+	ioprioClassIdle = 3
+
+	// taskDir is the directory holding the threads of the own process.
+	taskDir = "/proc/self/task"
+)
+
+// Settings are the scheduling settings of the IO and the CPU, with the zero
+// value keeping both unchanged.
+type Settings struct {
+	// IOClass is the IO scheduling class ([ClassNone], [ClassBestEffort] or
+	// [ClassIdle]).
+	IOClass string
+
+	// IOLevel is the priority level within the best-effort class, from
+	// [MinLevel] (highest) to [MaxLevel] (lowest).
+	IOLevel int
+
+	// Nice is the CPU niceness, from [MinNice] (highest) to [MaxNice] (lowest),
+	// with 0 keeping it unchanged.
+	Nice int
+}
+
+// IsZero returns whether the [Settings] keep everything unchanged.
+func (s Settings) IsZero() bool {
+	return s.IOClass == ClassNone && s.Nice == 0
+}
+
+// Validate returns an [ErrInvalidSettings] if the [Settings] are out of range.
+func (s Settings) Validate() error {
+	switch s.IOClass {
+	case ClassNone, ClassBestEffort, ClassIdle:
+	default:
+		return fmt.Errorf("(priority) %w: unknown class %q", ErrInvalidSettings, s.IOClass)
+	}
+
+	if s.IOLevel < MinLevel || s.IOLevel > MaxLevel {
+		return fmt.Errorf("(priority) %w: level %d not within %d-%d", ErrInvalidSettings, s.IOLevel, MinLevel, MaxLevel)
+	}
+
+	if s.Nice < MinNice || s.Nice > MaxNice {
+		return fmt.Errorf("(priority) %w: nice %d not within %d-%d", ErrInvalidSettings, s.Nice, MinNice, MaxNice)
+	}
+
+	return nil
+}
+
+// Apply applies the [Settings] to all threads of the own process, so that also
+// all threads (and subprocesses) created afterwards inherit them.
+func (s Settings) Apply() error {
+	if s.IsZero() {
+		return nil
+	}
+
+	tasks, err := os.ReadDir(taskDir)
+	if err != nil {
+		return fmt.Errorf("(priority) failed to list threads: %w", err)
+	}
+
+	var errs []error
+
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+
+		// A thread may have exited since the listing.
+		if err := s.ApplyPID(tid); err != nil && !errors.Is(err, unix.ESRCH) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyPID applies the [Settings] to a single thread or (single-threaded)
+// process, such as a spawned subprocess.
+func (s Settings) ApplyPID(pid int) error {
+	if s.IOClass != ClassNone {
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(s.ioprio())); errno != 0 {
+			return fmt.Errorf("(priority) failed to ioprio_set: %w", errno)
+		}
+	}
+
+	if s.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, pid, s.Nice); err != nil {
+			return fmt.Errorf("(priority) failed to setpriority: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ioprio returns the kernel's IO priority value of the [Settings].
+func (s Settings) ioprio() int {
+	if s.IOClass == ClassIdle {
+		return ioprioClassIdle << ioprioClassShift
+	}
+
+	return ioprioClassBE<<ioprioClassShift | s.IOLevel
+}
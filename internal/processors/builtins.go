@@ -0,0 +1,312 @@
+package processors
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/desertwitch/gover/internal/filesystem"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// directionAny is the direction of all [schema.Moveable].
+	directionAny = "any"
+
+	// directionToArray is the direction of [schema.Moveable] moving from their
+	// cache pool to the array.
+	directionToArray = "to-array"
+
+	// directionToCache is the direction of [schema.Moveable] moving from the
+	// array to their cache pool.
+	directionToCache = "to-cache"
+)
+
+// MoveableRegistry returns a pointer to a new [Registry] holding all built-in
+// processors for [schema.Moveable] (as used in the evaluation and IO
+// pipelines):
+//   - include(glob): keeps only those matching the glob.
+//   - exclude(glob): keeps only those not matching the glob.
+//   - min-size(size, direction=any|to-array|to-cache): keeps only files of at
+//     least the size (see [sizeFilter]).
+//   - max-size(size, direction=any|to-array|to-cache): keeps only files of at
+//     most the size (see [sizeFilter]).
+//   - min-age(age, by=mtime|atime|ctime, links=parent|all): keeps only those
+//     at least the age old (see [ageFilter]).
+//   - sort(by=path|size|mtime, order=asc|desc): sorts the batch.
+//   - limit(count): keeps only the first count of the batch.
+//
+// A glob is a [filesystem.PathPattern], matched against the share-relative
+// source path.
+func MoveableRegistry() *Registry[*schema.Moveable] {
+	return NewRegistry(moveableLogAttrs).
+		Register("include", Definition[*schema.Moveable]{Filter: globFilter(true, moveablePath)}).
+		Register("exclude", Definition[*schema.Moveable]{Filter: globFilter(false, moveablePath)}).
+		Register("min-size", Definition[*schema.Moveable]{Filter: sizeFilter(true)}).
+		Register("max-size", Definition[*schema.Moveable]{Filter: sizeFilter(false)}).
+		Register("min-age", Definition[*schema.Moveable]{Filter: ageFilter}).
+		Register("sort", Definition[*schema.Moveable]{Batch: sortMoveables}).
+		Register("limit", Definition[*schema.Moveable]{Batch: limitBatch[*schema.Moveable]})
+}
+
+// EnumerationRegistry returns a pointer to a new [Registry] holding all
+// built-in processors for [queue.EnumerationTask] (as used in the enumeration
+// pipelines):
+//   - include(glob): keeps only the shares with a name matching the glob.
+//   - exclude(glob): keeps only the shares with a name not matching the glob.
+//   - limit(count): keeps only the first count of the batch.
+func EnumerationRegistry() *Registry[*queue.EnumerationTask] {
+	return NewRegistry(enumerationLogAttrs).
+		Register("include", Definition[*queue.EnumerationTask]{Filter: globFilter(true, enumerationShareName)}).
+		Register("exclude", Definition[*queue.EnumerationTask]{Filter: globFilter(false, enumerationShareName)}).
+		Register("limit", Definition[*queue.EnumerationTask]{Batch: limitBatch[*queue.EnumerationTask]})
+}
+
+// moveableLogAttrs returns the log attributes describing a [schema.Moveable].
+func moveableLogAttrs(m *schema.Moveable) []any {
+	attrs := []any{"job", m.SourcePath}
+
+	if m.Share != nil {
+		attrs = append(attrs, "share", m.Share.GetName())
+	}
+
+	if m.Dest != nil {
+		attrs = append(attrs, "target", m.Dest.GetName())
+	}
+
+	return attrs
+}
+
+// enumerationLogAttrs returns the log attributes describing a
+// [queue.EnumerationTask].
+func enumerationLogAttrs(t *queue.EnumerationTask) []any {
+	return []any{"share", enumerationShareName(t)}
+}
+
+// moveablePath returns the share-relative source path of a [schema.Moveable],
+// or the full source path if it cannot be established.
+func moveablePath(m *schema.Moveable) string {
+	if m.Share == nil || m.Source == nil {
+		return m.SourcePath
+	}
+
+	rel, err := filepath.Rel(filepath.Join(m.Source.GetFSPath(), m.Share.GetName()), m.SourcePath)
+	if err != nil {
+		return m.SourcePath
+	}
+
+	return rel
+}
+
+// enumerationShareName returns the [schema.Share] name of a
+// [queue.EnumerationTask].
+func enumerationShareName(t *queue.EnumerationTask) string {
+	if t.Share == nil {
+		return ""
+	}
+
+	return t.Share.GetName()
+}
+
+// globFilter returns a filter for items matching (or not matching) a glob, as
+// given by the "glob" parameter (see [filesystem.PathPattern]).
+func globFilter[T any](keepMatching bool, getPathFunc func(T) string) func(p Params) (func(T) error, error) {
+	return func(p Params) (func(T) error, error) {
+		glob, err := p.Required("glob")
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, err := filesystem.ParsePathPattern(glob)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(item T) error {
+			if matched := pattern.Match(getPathFunc(item)); matched != keepMatching {
+				return fmt.Errorf("%w: %q (matched: %t)", ErrFilteredByGlob, glob, matched)
+			}
+
+			return nil
+		}, nil
+	}
+}
+
+// sizeFilter returns a filter for files of at least (or at most) a size, as
+// given by the "size" parameter. Any other [schema.Moveable] are kept.
+//
+// The "direction" parameter restricts the filter to [schema.Moveable] of
+// shares moving from their cache pool to the array ("to-array", as with
+// caching set to "yes") or from the array to their cache pool ("to-cache", as
+// with caching set to "prefer"), keeping all those moving in the other
+// direction.
+func sizeFilter(atLeast bool) func(p Params) (func(*schema.Moveable) error, error) {
+	return func(p Params) (func(*schema.Moveable) error, error) {
+		size, err := p.Size("size")
+		if err != nil {
+			return nil, err
+		}
+
+		direction, err := p.Choice("direction", directionAny, directionToArray, directionToCache)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(m *schema.Moveable) error {
+			if m.Metadata == nil || m.Metadata.IsDir || m.Metadata.IsSymlink {
+				return nil
+			}
+
+			if direction != directionAny && direction != directionOf(m) {
+				return nil
+			}
+
+			if atLeast && m.Metadata.Size < size {
+				return fmt.Errorf("%w: %s is below the minimum of %s", ErrFilteredBySize,
+					humanize.IBytes(m.Metadata.Size), humanize.IBytes(size))
+			}
+
+			if !atLeast && m.Metadata.Size > size {
+				return fmt.Errorf("%w: %s is above the maximum of %s", ErrFilteredBySize,
+					humanize.IBytes(m.Metadata.Size), humanize.IBytes(size))
+			}
+
+			return nil
+		}, nil
+	}
+}
+
+// directionOf returns the direction a [schema.Moveable] is moving in, as
+// derived from the caching setting of its [schema.Share].
+func directionOf(m *schema.Moveable) string {
+	if m.Share != nil && m.Share.GetUseCache() == "prefer" {
+		return directionToCache
+	}
+
+	return directionToArray
+}
+
+// ageFilter returns a filter for [schema.Moveable] being at least an age old,
+// as given by the "age" parameter. The age is measured by the time given in the
+// "by" parameter (modification, access or status change time).
+//
+// A [schema.Moveable] is always kept or filtered together with its hard- and
+// symlinks, so that these are never separated from it. By default, only the age
+// of the "parent" [schema.Moveable] decides (hardlinks share its times anyway).
+// With the "links" parameter set to "all", any of its hard- and symlinks being
+// too young also keeps the [schema.Moveable] (and all of its links) in place.
+func ageFilter(p Params) (func(*schema.Moveable) error, error) {
+	age, err := p.Duration("age")
+	if err != nil {
+		return nil, err
+	}
+
+	by, err := p.Choice("by", "mtime", "atime", "ctime")
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := p.Choice("links", "parent", "all")
+	if err != nil {
+		return nil, err
+	}
+
+	timeOf := func(m *schema.Moveable) time.Time {
+		metadata := metadataOf(m)
+
+		switch by {
+		case "atime":
+			return time.Unix(metadata.AccessedAt.Unix())
+		case "ctime":
+			return time.Unix(metadata.ChangedAt.Unix())
+		default:
+			return time.Unix(metadata.ModifiedAt.Unix())
+		}
+	}
+
+	return func(m *schema.Moveable) error {
+		cutoff := time.Now().Add(-age)
+
+		elems := []*schema.Moveable{m}
+		if links == "all" {
+			elems = append(elems, m.Hardlinks...)
+			elems = append(elems, m.Symlinks...)
+		}
+
+		for _, elem := range elems {
+			if t := timeOf(elem); t.After(cutoff) {
+				return fmt.Errorf("%w: %s of %s is younger than %s", ErrFilteredByAge,
+					by, elem.SourcePath, age)
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+// sortMoveables returns a [schema.BatchProcessor] sorting [schema.Moveable] by
+// the "by" parameter in the "order" parameter.
+func sortMoveables(p Params) (schema.BatchProcessor[*schema.Moveable], error) {
+	by, err := p.Choice("by", "path", "size", "mtime")
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := p.Choice("order", "asc", "desc")
+	if err != nil {
+		return nil, err
+	}
+
+	less := func(a, b *schema.Moveable) bool {
+		switch by {
+		case "size":
+			return metadataOf(a).Size < metadataOf(b).Size
+		case "mtime":
+			return metadataOf(a).ModifiedAt.Nano() < metadataOf(b).ModifiedAt.Nano()
+		default:
+			return a.SourcePath < b.SourcePath
+		}
+	}
+
+	return func(items []*schema.Moveable) ([]*schema.Moveable, bool) {
+		sort.SliceStable(items, func(i, j int) bool {
+			if order == "desc" {
+				return less(items[j], items[i])
+			}
+
+			return less(items[i], items[j])
+		})
+
+		return items, true
+	}, nil
+}
+
+// limitBatch returns a [schema.BatchProcessor] keeping only the first items of
+// a batch, as given by the "count" parameter.
+func limitBatch[T any](p Params) (schema.BatchProcessor[T], error) {
+	count, err := p.Int("count")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(items []T) ([]T, bool) {
+		if len(items) > count {
+			return items[:count], true
+		}
+
+		return items, true
+	}, nil
+}
+
+// metadataOf returns the [schema.Metadata] of a [schema.Moveable], or empty
+// [schema.Metadata] if it has none.
+func metadataOf(m *schema.Moveable) *schema.Metadata {
+	if m.Metadata == nil {
+		return &schema.Metadata{}
+	}
+
+	return m.Metadata
+}
@@ -0,0 +1,34 @@
+package processors
+
+import "errors"
+
+var (
+	// ErrInvalidRule occurs when a [Rule] is malformed or references an
+	// unknown stage or phase.
+	ErrInvalidRule = errors.New("invalid rule")
+
+	// ErrUnknownProcessor occurs when a [Rule] references a processor that is
+	// not registered for its stage.
+	ErrUnknownProcessor = errors.New("unknown processor")
+
+	// ErrInvalidPhase occurs when a [Rule] references a processor that cannot
+	// be used in its phase.
+	ErrInvalidPhase = errors.New("processor not usable in phase")
+
+	// ErrInvalidParam occurs when a [Rule] gives a missing or invalid parameter
+	// to its processor.
+	ErrInvalidParam = errors.New("invalid parameter")
+
+	// ErrUnknownPolicy occurs when an ordering policy is not one of the
+	// [OrderPolicies].
+	ErrUnknownPolicy = errors.New("unknown ordering policy")
+
+	// ErrFilteredByGlob occurs when an item is filtered by a glob filter.
+	ErrFilteredByGlob = errors.New("filtered by glob")
+
+	// ErrFilteredBySize occurs when an item is filtered by a size filter.
+	ErrFilteredBySize = errors.New("filtered by size")
+
+	// ErrFilteredByAge occurs when an item is filtered by an age filter.
+	ErrFilteredByAge = errors.New("filtered by age")
+)
@@ -49,15 +49,15 @@ func (p *GenericPipeline[T]) AddPostProcess(processor schema.BatchProcessor[T])
 // within the structure responsible for the given T, such as a queue or
 // queue-related processing function.
 //
-// The first failed processor will cause the function to return with "false".
-func (p *GenericPipeline[T]) Process(item T) bool {
+// The first failed processor will cause the function to return with its error.
+func (p *GenericPipeline[T]) Process(item T) error {
 	for _, fn := range p.itemProcessors {
-		if success := fn(item); !success {
-			return false
+		if err := fn(item); err != nil {
+			return err
 		}
 	}
 
-	return true
+	return nil
 }
 
 // PreProcess sequentially runs all previously added [schema.BatchProcessor]
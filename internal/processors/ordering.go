@@ -0,0 +1,195 @@
+package processors
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// OrderFIFO keeps the enumeration order of the [schema.Moveable].
+	OrderFIFO = "fifo"
+
+	// OrderSizeDesc orders the [schema.Moveable] by size, largest first.
+	OrderSizeDesc = "size-desc"
+
+	// OrderSizeAsc orders the [schema.Moveable] by size, smallest first.
+	OrderSizeAsc = "size-asc"
+
+	// OrderMtime orders the [schema.Moveable] by modification time, oldest
+	// first.
+	OrderMtime = "mtime"
+
+	// OrderAtime orders the [schema.Moveable] by access time, oldest first.
+	OrderAtime = "atime"
+
+	// OrderSharePriority orders the [schema.Moveable] by the explicit priority
+	// of their [schema.Share], with shares without a priority coming last.
+	OrderSharePriority = "share-priority"
+
+	// OrderRoundRobin interleaves the (otherwise ordered) [schema.Moveable] of
+	// all [schema.Share], taking one of every share in turn.
+	OrderRoundRobin = "round-robin"
+)
+
+// OrderPolicies are all the known ordering policies.
+var OrderPolicies = []string{
+	OrderFIFO,
+	OrderSizeDesc,
+	OrderSizeAsc,
+	OrderMtime,
+	OrderAtime,
+	OrderSharePriority,
+	OrderRoundRobin,
+}
+
+// ParseOrderPolicies parses comma-separated ordering policies (e.g.
+// "share-priority,size-desc"), returning an [ErrUnknownPolicy] for any that
+// are not one of [OrderPolicies].
+func ParseOrderPolicies(s string) ([]string, error) {
+	var policies []string
+
+	for policy := range strings.SplitSeq(s, ",") {
+		policy = strings.TrimSpace(policy)
+		if policy == "" {
+			continue
+		}
+
+		if !slices.Contains(OrderPolicies, policy) {
+			return nil, fmt.Errorf("(processors-ordering) %w: %q is not one of %q", ErrUnknownPolicy, policy, OrderPolicies)
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// NewOrdering returns a function ordering [schema.Moveable] by the given
+// ordering policies (see [ParseOrderPolicies]), or nil if they keep the
+// enumeration order. The policies are applied as a stable sort with every
+// further policy breaking the ties of the previous ones, except for
+// [OrderRoundRobin], which always interleaves the shares as a last step.
+//
+// The share priority is given by the order of the share names (with the first
+// being the most important). If there is a share priority but the policies do
+// not include [OrderSharePriority], it is applied before all other policies.
+func NewOrdering(policies []string, sharePriority []string) func(items []*schema.Moveable) []*schema.Moveable {
+	if len(sharePriority) > 0 && !slices.Contains(policies, OrderSharePriority) {
+		policies = append([]string{OrderSharePriority}, policies...)
+	}
+
+	ranks := make(map[string]int, len(sharePriority))
+	for i, name := range sharePriority {
+		if _, exists := ranks[name]; !exists {
+			ranks[name] = i
+		}
+	}
+
+	rankOf := func(m *schema.Moveable) int {
+		if m.Share != nil {
+			if rank, exists := ranks[m.Share.GetName()]; exists {
+				return rank
+			}
+		}
+
+		return len(sharePriority)
+	}
+
+	var compares []func(a, b *schema.Moveable) int
+	roundRobin := false
+
+	for _, policy := range policies {
+		switch policy {
+		case OrderSizeDesc:
+			compares = append(compares, func(a, b *schema.Moveable) int {
+				return cmp.Compare(metadataOf(b).Size, metadataOf(a).Size)
+			})
+		case OrderSizeAsc:
+			compares = append(compares, func(a, b *schema.Moveable) int {
+				return cmp.Compare(metadataOf(a).Size, metadataOf(b).Size)
+			})
+		case OrderMtime:
+			compares = append(compares, func(a, b *schema.Moveable) int {
+				return cmp.Compare(metadataOf(a).ModifiedAt.Nano(), metadataOf(b).ModifiedAt.Nano())
+			})
+		case OrderAtime:
+			compares = append(compares, func(a, b *schema.Moveable) int {
+				return cmp.Compare(metadataOf(a).AccessedAt.Nano(), metadataOf(b).AccessedAt.Nano())
+			})
+		case OrderSharePriority:
+			compares = append(compares, func(a, b *schema.Moveable) int {
+				return cmp.Compare(rankOf(a), rankOf(b))
+			})
+		case OrderRoundRobin:
+			roundRobin = true
+		}
+	}
+
+	if len(compares) == 0 && !roundRobin {
+		return nil
+	}
+
+	return func(items []*schema.Moveable) []*schema.Moveable {
+		if len(compares) > 0 {
+			slices.SortStableFunc(items, func(a, b *schema.Moveable) int {
+				for _, compare := range compares {
+					if c := compare(a, b); c != 0 {
+						return c
+					}
+				}
+
+				return 0
+			})
+		}
+
+		if roundRobin {
+			items = interleaveShares(items, rankOf)
+		}
+
+		return items
+	}
+}
+
+// interleaveShares interleaves [schema.Moveable] by their [schema.Share],
+// taking one of every share in turn while keeping their order within each
+// share. The turns follow the share priority, and otherwise the order in which
+// the shares first appear.
+func interleaveShares(items []*schema.Moveable, rankOf func(m *schema.Moveable) int) []*schema.Moveable {
+	var names []string
+	groups := make(map[string][]*schema.Moveable)
+	ranks := make(map[string]int)
+
+	for _, m := range items {
+		name := ""
+		if m.Share != nil {
+			name = m.Share.GetName()
+		}
+
+		if _, exists := groups[name]; !exists {
+			names = append(names, name)
+			ranks[name] = rankOf(m)
+		}
+		groups[name] = append(groups[name], m)
+	}
+
+	slices.SortStableFunc(names, func(a, b string) int {
+		return cmp.Compare(ranks[a], ranks[b])
+	})
+
+	interleaved := make([]*schema.Moveable, 0, len(items))
+
+	for len(interleaved) < len(items) {
+		for _, name := range names {
+			if group := groups[name]; len(group) > 0 {
+				interleaved = append(interleaved, group[0])
+				groups[name] = group[1:]
+			}
+		}
+	}
+
+	return interleaved
+}
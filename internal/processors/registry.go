@@ -0,0 +1,235 @@
+package processors
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/gover/internal/schema"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// hoursPerDay is the amount of hours of a day (as used in durations).
+	hoursPerDay = 24
+)
+
+// Params are the named parameters given to a built-in processor by a [Rule].
+type Params map[string]string
+
+// String returns the string value of a parameter, or the default value if the
+// parameter was not given.
+func (p Params) String(key string, def string) string {
+	if value, exists := p[key]; exists {
+		return value
+	}
+
+	return def
+}
+
+// Required returns the string value of a parameter, or an [ErrInvalidParam] if
+// the parameter was not given (or is empty).
+func (p Params) Required(key string) (string, error) {
+	value := p[key]
+	if value == "" {
+		return "", fmt.Errorf("(processors-params) %w: %s is required", ErrInvalidParam, key)
+	}
+
+	return value, nil
+}
+
+// Int returns the positive integer value of a required parameter.
+func (p Params) Int(key string) (int, error) {
+	value, err := p.Required(key)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		return 0, fmt.Errorf("(processors-params) %w: %s=%q is not a positive integer", ErrInvalidParam, key, value)
+	}
+
+	return i, nil
+}
+
+// Size returns the size value (e.g. "512", "10MB" or "1GiB") of a required
+// parameter in bytes.
+func (p Params) Size(key string) (uint64, error) {
+	value, err := p.Required(key)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := humanize.ParseBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("(processors-params) %w: %s=%q is not a size", ErrInvalidParam, key, value)
+	}
+
+	return size, nil
+}
+
+// Duration returns the duration value (e.g. "36h", "90m" or "30d", with "d"
+// being days) of a required parameter.
+func (p Params) Duration(key string) (time.Duration, error) {
+	value, err := p.Required(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var d time.Duration
+
+	if days, isDays := strings.CutSuffix(value, "d"); isDays {
+		var n float64
+		n, err = strconv.ParseFloat(days, 64)
+		d = time.Duration(n * float64(hoursPerDay*time.Hour))
+	} else {
+		d, err = time.ParseDuration(value)
+	}
+
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("(processors-params) %w: %s=%q is not a duration", ErrInvalidParam, key, value)
+	}
+
+	return d, nil
+}
+
+// Choice returns the value of a parameter that must be one of the given
+// choices, with the first choice being the default.
+func (p Params) Choice(key string, choices ...string) (string, error) {
+	value := p.String(key, choices[0])
+
+	for _, choice := range choices {
+		if value == choice {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("(processors-params) %w: %s=%q is not one of %q", ErrInvalidParam, key, value, choices)
+}
+
+// Definition describes a named built-in processor of a [Registry]. A filter
+// keeps only the items its predicate returns no error for (with the error being
+// the reason for filtering an item) and can be used in any phase, whereas a
+// batch processor can only be used in the pre- and post-processing phases.
+type Definition[T any] struct {
+	// Filter returns the predicate of a filter for the given [Params].
+	Filter func(p Params) (func(item T) error, error)
+
+	// Batch returns the [schema.BatchProcessor] for the given [Params].
+	Batch func(p Params) (schema.BatchProcessor[T], error)
+}
+
+// Registry holds the [Definition] of all named built-in processors for one
+// type of pipeline items.
+type Registry[T any] struct {
+	definitions map[string]Definition[T]
+	logAttrs    func(item T) []any
+}
+
+// NewRegistry returns a pointer to a new (empty) [Registry]. The given function
+// returns the log attributes describing an item that was filtered.
+func NewRegistry[T any](logAttrsFunc func(item T) []any) *Registry[T] {
+	return &Registry[T]{
+		definitions: make(map[string]Definition[T]),
+		logAttrs:    logAttrsFunc,
+	}
+}
+
+// Register adds a [Definition] under the given name to the [Registry],
+// replacing any previous [Definition] of that name.
+func (r *Registry[T]) Register(name string, d Definition[T]) *Registry[T] {
+	r.definitions[name] = d
+
+	return r
+}
+
+// Names returns the names of all registered [Definition], sorted by name.
+func (r *Registry[T]) Names() []string {
+	names := make([]string, 0, len(r.definitions))
+	for name := range r.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Processor returns a [schema.Processor] of a named filter for the given
+// [Params], which fails (skips) all items that are not kept by the filter, with
+// the reason for their filtering. Every skipped item is also logged (not as an
+// error), with that reason.
+func (r *Registry[T]) Processor(name string, p Params) (schema.Processor[T], error) {
+	d, exists := r.definitions[name]
+	if !exists {
+		return nil, fmt.Errorf("(processors-registry) %w: %s", ErrUnknownProcessor, name)
+	}
+
+	if d.Filter == nil {
+		return nil, fmt.Errorf("(processors-registry) %w: %s can only pre- or post-process", ErrInvalidPhase, name)
+	}
+
+	filter, err := d.Filter(p)
+	if err != nil {
+		return nil, fmt.Errorf("(processors-registry) %s: %w", name, err)
+	}
+
+	return func(item T) error {
+		if reason := filter(item); reason != nil {
+			slog.Info("Skipped job: filtered by rule",
+				append([]any{"reason", reason, "processor", name}, r.logAttrs(item)...)...,
+			)
+
+			return reason
+		}
+
+		return nil
+	}, nil
+}
+
+// BatchProcessor returns a [schema.BatchProcessor] of a named filter or batch
+// processor for the given [Params]. A filter removes all items that are not
+// kept by it from the batch (without these counting as skipped), with every
+// removed item being logged (not as an error), with the reason for its
+// filtering.
+func (r *Registry[T]) BatchProcessor(name string, p Params) (schema.BatchProcessor[T], error) {
+	d, exists := r.definitions[name]
+	if !exists {
+		return nil, fmt.Errorf("(processors-registry) %w: %s", ErrUnknownProcessor, name)
+	}
+
+	if d.Batch != nil {
+		batch, err := d.Batch(p)
+		if err != nil {
+			return nil, fmt.Errorf("(processors-registry) %s: %w", name, err)
+		}
+
+		return batch, nil
+	}
+
+	filter, err := d.Filter(p)
+	if err != nil {
+		return nil, fmt.Errorf("(processors-registry) %s: %w", name, err)
+	}
+
+	return func(items []T) ([]T, bool) {
+		kept := make([]T, 0, len(items))
+
+		for _, item := range items {
+			if reason := filter(item); reason != nil {
+				slog.Info("Filtered job: filtered by rule",
+					append([]any{"reason", reason, "processor", name}, r.logAttrs(item)...)...,
+				)
+
+				continue
+			}
+
+			kept = append(kept, item)
+		}
+
+		return kept, true
+	}, nil
+}
@@ -0,0 +1,285 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/desertwitch/gover/internal/configuration"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+const (
+	// StageEnumeration is the stage of the enumeration pipelines, keyed by
+	// source [schema.Storage].
+	StageEnumeration = "enumeration"
+
+	// StageEvaluation is the stage of the evaluation pipelines, keyed by
+	// [schema.Share].
+	StageEvaluation = "evaluation"
+
+	// StageIO is the stage of the IO pipelines, keyed by target
+	// [schema.Storage].
+	StageIO = "io"
+
+	// PhasePre is the phase of the pre-processors ([schema.BatchProcessor]),
+	// running before any item of a queue is processed.
+	PhasePre = "pre"
+
+	// PhaseProcess is the phase of the processors ([schema.Processor]),
+	// running for every item of a queue. Items failing it are skipped.
+	PhaseProcess = "process"
+
+	// PhasePost is the phase of the post-processors ([schema.BatchProcessor]),
+	// running on the successfully processed items of a queue.
+	PhasePost = "post"
+
+	// AnyKey is the key of a [Rule] applying to all sources, shares or targets
+	// of its stage.
+	AnyKey = "*"
+)
+
+// Rule is a declarative rule adding a named built-in processor (with its
+// [Params]) to a phase of the pipeline of a stage for a specific key (or for
+// all of them). The textual representation of a rule is:
+//
+//	stage[:key] phase processor[(param=value, ...)]
+//
+// For example "evaluation:media pre exclude(glob=*.tmp)" or "io post
+// sort(by=size, order=desc)". Parameter values cannot contain commas or
+// parentheses.
+type Rule struct {
+	Stage     string
+	Key       string
+	Phase     string
+	Processor string
+	Params    Params
+
+	// Text is the textual representation the rule was parsed from.
+	Text string
+}
+
+// ParseRules parses [Rule] from their textual representations, separated by
+// newlines or semicolons (with empty lines and those starting with "#" being
+// ignored). Every [Rule] is validated against the built-in processors of its
+// stage. All errors are joined and returned together.
+func ParseRules(s string) ([]*Rule, error) {
+	var rules []*Rule
+	var errs []error
+
+	for line := range strings.FieldsFuncSeq(s, func(r rune) bool { return r == '\n' || r == ';' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule, err := parseRule(line)
+		if err == nil {
+			err = rule.validate()
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("(processors-rules) %q: %w", line, err))
+
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return rules, nil
+}
+
+// CompileRules compiles [Rule] into the pipelines of a
+// [configuration.PipelineConfiguration], establishing any missing pipelines
+// for the names of the given sources, shares and targets. Rules referencing an
+// unknown name are skipped with a warning.
+func CompileRules(rules []*Rule, sources, shares, targets []string, pipelines *configuration.PipelineConfiguration) error {
+	enumRegistry := EnumerationRegistry()
+	moveableRegistry := MoveableRegistry()
+
+	for _, r := range rules {
+		var err error
+
+		switch r.Stage {
+		case StageEnumeration:
+			err = addRule(enumRegistry, pipelines.EnumerationPipelines, sources, r)
+
+		case StageEvaluation:
+			err = addRule(moveableRegistry, pipelines.EvaluationPipelines, shares, r)
+
+		case StageIO:
+			err = addRule(moveableRegistry, pipelines.IOPipelines, targets, r)
+
+		default:
+			err = fmt.Errorf("%w: unknown stage %s", ErrInvalidRule, r.Stage)
+		}
+
+		if err != nil {
+			return fmt.Errorf("(processors-rules) %q: %w", r.Text, err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks a [Rule] by building its processor once (which is then
+// discarded).
+func (r *Rule) validate() error {
+	switch r.Stage {
+	case StageEnumeration:
+		return addRule(EnumerationRegistry(), make(map[string]schema.Pipeline[*queue.EnumerationTask]), nil, r)
+
+	case StageEvaluation, StageIO:
+		return addRule(MoveableRegistry(), make(map[string]schema.Pipeline[*schema.Moveable]), nil, r)
+
+	default:
+		return fmt.Errorf("%w: unknown stage %s", ErrInvalidRule, r.Stage)
+	}
+}
+
+// addRule adds the processor of a [Rule] to the pipelines of all matching
+// names, establishing them where missing. Without any names given, the
+// processor is only built (for validation).
+func addRule[T any](registry *Registry[T], pipelines map[string]schema.Pipeline[T], names []string, r *Rule) error {
+	matched := false
+
+	for _, name := range append([]string{""}, names...) {
+		if name != "" && r.Key != AnyKey && r.Key != name {
+			continue
+		}
+
+		pipeline, exists := pipelines[name]
+		if !exists {
+			pipeline = &GenericPipeline[T]{}
+		}
+
+		switch r.Phase {
+		case PhasePre, PhasePost:
+			batch, err := registry.BatchProcessor(r.Processor, r.Params)
+			if err != nil {
+				return err
+			}
+
+			if r.Phase == PhasePre {
+				pipeline.AddPreProcess(batch)
+			} else {
+				pipeline.AddPostProcess(batch)
+			}
+
+		case PhaseProcess:
+			processor, err := registry.Processor(r.Processor, r.Params)
+			if err != nil {
+				return err
+			}
+			pipeline.Add(processor)
+
+		default:
+			return fmt.Errorf("%w: unknown phase %s", ErrInvalidRule, r.Phase)
+		}
+
+		if name != "" {
+			pipelines[name] = pipeline
+			matched = true
+		}
+	}
+
+	if len(names) > 0 && !matched {
+		slog.Warn("Skipped rule: no matching name",
+			"rule", r.Text,
+			"key", r.Key,
+		)
+	}
+
+	return nil
+}
+
+// parseRule parses a single [Rule] from its textual representation.
+func parseRule(text string) (*Rule, error) {
+	stageKey, rest := cutField(text)
+	phase, spec := cutField(rest)
+
+	if stageKey == "" || phase == "" || spec == "" {
+		return nil, fmt.Errorf("%w: expected \"stage[:key] phase processor[(params)]\"", ErrInvalidRule)
+	}
+
+	stage, key, hasKey := strings.Cut(stageKey, ":")
+	if !hasKey {
+		key = AnyKey
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidRule)
+	}
+
+	name, params, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Rule{
+		Stage:     stage,
+		Key:       key,
+		Phase:     phase,
+		Processor: name,
+		Params:    params,
+		Text:      text,
+	}, nil
+}
+
+// parseSpec parses a processor specification "name[(param=value, ...)]".
+func parseSpec(spec string) (string, Params, error) {
+	params := make(Params)
+
+	name, paramList, hasParams := strings.Cut(spec, "(")
+	name = strings.TrimSpace(name)
+
+	if name == "" || strings.ContainsFunc(name, unicode.IsSpace) {
+		return "", nil, fmt.Errorf("%w: invalid processor name %q", ErrInvalidRule, name)
+	}
+
+	if !hasParams {
+		return name, params, nil
+	}
+
+	paramList, found := strings.CutSuffix(strings.TrimSpace(paramList), ")")
+	if !found || strings.ContainsAny(paramList, "()") {
+		return "", nil, fmt.Errorf("%w: unbalanced parentheses", ErrInvalidRule)
+	}
+
+	for pair := range strings.SplitSeq(paramList, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || key == "" {
+			return "", nil, fmt.Errorf("%w: invalid parameter %q", ErrInvalidRule, strings.TrimSpace(pair))
+		}
+
+		params[key] = strings.TrimSpace(value)
+	}
+
+	return name, params, nil
+}
+
+// cutField returns the first whitespace-separated field of a string and the
+// (trimmed) remainder following it.
+func cutField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+
+	return s, ""
+}
@@ -0,0 +1,13 @@
+package progress
+
+import "errors"
+
+var (
+	// ErrUnknownFormat occurs when a [Reporter] is requested for an unknown
+	// format.
+	ErrUnknownFormat = errors.New("unknown progress format")
+
+	// ErrInvalidInterval occurs when a [Reporter] is requested for a
+	// non-positive interval.
+	ErrInvalidInterval = errors.New("invalid progress interval")
+)
@@ -0,0 +1,156 @@
+// Package progress implements routines for collecting the [queue.Progress] of
+// all managers and queues of a [queue.Manager] into snapshots, as well as for
+// reporting such snapshots in machine- and human-readable formats.
+package progress
+
+import (
+	"sort"
+	"time"
+
+	"github.com/desertwitch/gover/internal/queue"
+)
+
+const (
+	// StageEnumeration is the stage name of the [queue.EnumerationManager].
+	StageEnumeration = "enumeration"
+
+	// StageEvaluation is the stage name of the [queue.EvaluationManager].
+	StageEvaluation = "evaluation"
+
+	// StageIO is the stage name of the [queue.IOManager].
+	StageIO = "io"
+)
+
+// namedKey defines the methods a key of a managed queue needs to have.
+type namedKey interface {
+	comparable
+	GetName() string
+}
+
+// progressQueue defines the methods a managed queue needs to have.
+type progressQueue interface {
+	Progress() queue.Progress
+}
+
+// Record is the machine-readable representation of a [queue.Progress] for
+// either a manager (without queue name) or a queue of that manager.
+type Record struct {
+	Time              time.Time `json:"time"`
+	Stage             string    `json:"stage"`
+	Queue             string    `json:"queue,omitempty"`
+	HasStarted        bool      `json:"hasStarted"`
+	HasFinished       bool      `json:"hasFinished"`
+	StartTime         time.Time `json:"startTime"`
+	FinishTime        time.Time `json:"finishTime"`
+	ProgressPct       float64   `json:"progressPct"`
+	TotalItems        int       `json:"totalItems"`
+	ProcessedItems    int       `json:"processedItems"`
+	InProgressItems   int       `json:"inProgressItems"`
+	SuccessItems      int       `json:"successItems"`
+	SkippedItems      int       `json:"skippedItems"`
+	ETA               time.Time `json:"eta"`
+	TimeLeftSeconds   float64   `json:"timeLeftSeconds"`
+	TransferSpeed     float64   `json:"transferSpeed"`
+	TransferSpeedUnit string    `json:"transferSpeedUnit"`
+	BytesTransferred  uint64    `json:"bytesTransferred,omitempty"`
+}
+
+// Snapshot is a collection of [Record] of all managers and queues of a
+// [queue.Manager] at one specific point in time.
+type Snapshot struct {
+	// Time is the time the snapshot was taken at.
+	Time time.Time `json:"time"`
+
+	// Enumeration is the [Record] of the [queue.EnumerationManager].
+	Enumeration Record `json:"enumeration"`
+
+	// Evaluation is the [Record] of the [queue.EvaluationManager].
+	Evaluation Record `json:"evaluation"`
+
+	// IO is the [Record] of the [queue.IOManager].
+	IO Record `json:"io"`
+
+	// Queues are the [Record] of all managed queues, sorted by stage and name.
+	Queues []Record `json:"queues"`
+}
+
+// Collect returns a new [Snapshot] of a [queue.Manager].
+func Collect(queueManager *queue.Manager) *Snapshot {
+	t := time.Now()
+
+	s := &Snapshot{
+		Time:        t,
+		Enumeration: newRecord(t, StageEnumeration, "", queueManager.EnumerationManager.Progress()),
+		Evaluation:  newRecord(t, StageEvaluation, "", queueManager.EvaluationManager.Progress()),
+		IO:          newRecord(t, StageIO, "", queueManager.IOManager.Progress()),
+	}
+
+	s.Queues = append(s.Queues, collectQueues(t, StageEnumeration, queueManager.EnumerationManager.GetQueues())...)
+	s.Queues = append(s.Queues, collectQueues(t, StageEvaluation, queueManager.EvaluationManager.GetQueues())...)
+
+	ioQueues := queueManager.IOManager.GetQueues()
+	ioRecords := collectQueues(t, StageIO, ioQueues)
+
+	bytesTransferred := make(map[string]uint64, len(ioQueues))
+	for target, targetQueue := range ioQueues {
+		bytesTransferred[target.GetName()] = targetQueue.GetBytesTransfered()
+		s.IO.BytesTransferred += bytesTransferred[target.GetName()]
+	}
+
+	for i := range ioRecords {
+		ioRecords[i].BytesTransferred = bytesTransferred[ioRecords[i].Queue]
+	}
+
+	s.Queues = append(s.Queues, ioRecords...)
+
+	return s
+}
+
+// Records returns all [Record] of the [Snapshot], starting with those of the
+// managers, followed by those of their queues.
+func (s *Snapshot) Records() []Record {
+	records := make([]Record, 0, len(s.Queues)+3) //nolint:mnd
+	records = append(records, s.Enumeration, s.Evaluation, s.IO)
+	records = append(records, s.Queues...)
+
+	return records
+}
+
+// collectQueues returns a [Record] for each of the given queues, sorted by the
+// names of their keys.
+func collectQueues[K namedKey, Q progressQueue](t time.Time, stage string, queues map[K]Q) []Record {
+	records := make([]Record, 0, len(queues))
+
+	for key, q := range queues {
+		records = append(records, newRecord(t, stage, key.GetName(), q.Progress()))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Queue < records[j].Queue
+	})
+
+	return records
+}
+
+// newRecord returns a new [Record] for a [queue.Progress].
+func newRecord(t time.Time, stage string, queueName string, p queue.Progress) Record {
+	return Record{
+		Time:              t,
+		Stage:             stage,
+		Queue:             queueName,
+		HasStarted:        p.HasStarted,
+		HasFinished:       p.HasFinished,
+		StartTime:         p.StartTime,
+		FinishTime:        p.FinishTime,
+		ProgressPct:       p.ProgressPct,
+		TotalItems:        p.TotalItems,
+		ProcessedItems:    p.ProcessedItems,
+		InProgressItems:   p.InProgressItems,
+		SuccessItems:      p.SuccessItems,
+		SkippedItems:      p.SkippedItems,
+		ETA:               p.ETA,
+		TimeLeftSeconds:   p.TimeLeft.Seconds(),
+		TransferSpeed:     p.TransferSpeed,
+		TransferSpeedUnit: p.TransferSpeedUnit,
+	}
+}
@@ -0,0 +1,133 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// FormatJSON is the [Reporter] format emitting one NDJSON line per
+	// [Record] of each [Snapshot].
+	FormatJSON = "json"
+
+	// FormatText is the [Reporter] format emitting one plain human-readable
+	// line per [Snapshot].
+	FormatText = "text"
+)
+
+// Reporter periodically collects a [Snapshot] of a [queue.Manager] and writes
+// it in a given format to an [io.Writer].
+type Reporter struct {
+	queueManager *queue.Manager
+	writer       io.Writer
+	format       string
+	interval     time.Duration
+}
+
+// NewReporter returns a pointer to a new [Reporter]. An [ErrUnknownFormat] is
+// returned for a format other than [FormatJSON] or [FormatText].
+func NewReporter(queueManager *queue.Manager, writer io.Writer, format string, interval time.Duration) (*Reporter, error) {
+	if format != FormatJSON && format != FormatText {
+		return nil, fmt.Errorf("(progress) %w: %s", ErrUnknownFormat, format)
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("(progress) %w: %s", ErrInvalidInterval, interval)
+	}
+
+	return &Reporter{
+		queueManager: queueManager,
+		writer:       writer,
+		format:       format,
+		interval:     interval,
+	}, nil
+}
+
+// Run writes a [Snapshot] at every interval until the given context is
+// cancelled, at which point a last (final) [Snapshot] is written.
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = r.Report()
+
+			return
+		case <-ticker.C:
+			_ = r.Report()
+		}
+	}
+}
+
+// Report collects and writes a single [Snapshot].
+func (r *Reporter) Report() error {
+	s := Collect(r.queueManager)
+
+	if r.format == FormatJSON {
+		return WriteJSON(r.writer, s)
+	}
+
+	return WriteText(r.writer, s)
+}
+
+// WriteJSON writes all [Record] of a [Snapshot] as NDJSON, one line each.
+func WriteJSON(w io.Writer, s *Snapshot) error {
+	enc := json.NewEncoder(w)
+
+	for _, record := range s.Records() {
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("(progress-json) failed to encode: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteText writes a [Snapshot] as a single plain human-readable line.
+func WriteText(w io.Writer, s *Snapshot) error {
+	stages := make([]string, 0, 3) //nolint:mnd
+
+	for _, record := range []Record{s.Enumeration, s.Evaluation, s.IO} {
+		stages = append(stages, formatRecord(record))
+	}
+
+	if _, err := fmt.Fprintf(w, "[%s] %s\n", s.Time.Format(time.TimeOnly), strings.Join(stages, " | ")); err != nil {
+		return fmt.Errorf("(progress-text) failed to write: %w", err)
+	}
+
+	return nil
+}
+
+// formatRecord returns the human-readable representation of a [Record].
+func formatRecord(r Record) string {
+	status := fmt.Sprintf("%s %.1f%% (%d/%d)", r.Stage, r.ProgressPct, r.ProcessedItems, r.TotalItems)
+
+	switch {
+	case !r.HasStarted:
+		return status + " waiting"
+
+	case r.HasFinished:
+		return status + " done"
+
+	case r.TransferSpeedUnit == "bytes/sec":
+		status += fmt.Sprintf(" %s/s", humanize.IBytes(uint64(r.TransferSpeed)))
+
+	default:
+		status += fmt.Sprintf(" %.1f %s", r.TransferSpeed, r.TransferSpeedUnit)
+	}
+
+	if r.TimeLeftSeconds > 0 {
+		status += fmt.Sprintf(" ETA %s", time.Duration(r.TimeLeftSeconds*float64(time.Second)).Round(time.Second))
+	}
+
+	return status
+}
@@ -8,11 +8,12 @@ import (
 type EnumerationTask struct {
 	Share    schema.Share
 	Source   schema.Storage
-	Function func() int
+	Function func() (int, error)
 }
 
-// Run executes the stored enumeration function of an [EnumerationTask].
-func (e *EnumerationTask) Run() int {
+// Run executes the stored enumeration function of an [EnumerationTask],
+// returning its decision and the error that has led to it (if any).
+func (e *EnumerationTask) Run() (int, error) {
 	return e.Function()
 }
 
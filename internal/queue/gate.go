@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Gate allows for pausing and resuming the processing of queues. A paused gate
+// blocks any further items from being dequeued, while letting the already
+// in-progress items finish. A nil gate is never paused.
+type Gate struct {
+	sync.Mutex
+
+	paused     bool
+	resumeChan chan struct{}
+}
+
+// NewGate returns a pointer to a new (not paused) [Gate].
+func NewGate() *Gate {
+	return &Gate{}
+}
+
+// Pause pauses the [Gate], if it is not already paused.
+func (g *Gate) Pause() {
+	g.Lock()
+	defer g.Unlock()
+
+	if !g.paused {
+		g.paused = true
+		g.resumeChan = make(chan struct{})
+	}
+}
+
+// Resume resumes the [Gate], if it is paused.
+func (g *Gate) Resume() {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.paused {
+		g.paused = false
+		close(g.resumeChan)
+	}
+}
+
+// IsPaused returns whether the [Gate] is paused.
+func (g *Gate) IsPaused() bool {
+	if g == nil {
+		return false
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	return g.paused
+}
+
+// Wait blocks for as long as the [Gate] is paused. An error is only returned
+// in case of a context cancellation while waiting.
+func (g *Gate) Wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+
+	g.Lock()
+	if !g.paused {
+		g.Unlock()
+
+		return nil
+	}
+	resumeChan := g.resumeChan
+	g.Unlock()
+
+	select {
+	case <-resumeChan:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("(queue-gate) %w", ctx.Err())
+	}
+}
@@ -11,13 +11,17 @@ type GenericQueueType[V comparable] interface {
 	Enqueue(items ...V)
 	GetSuccessful() []V
 	Progress() Progress
+	setGate(g *Gate)
+	setErrorFunc(errFunc func(item V, err error))
 }
 
 // GenericManager is a generic queue manager for queues of [GenericQueueType].
 type GenericManager[K comparable, V comparable, Q GenericQueueType[V]] struct {
 	sync.RWMutex
 
-	queues map[K]Q
+	queues  map[K]Q
+	gate    *Gate
+	errFunc func(item V, err error)
 }
 
 // NewGenericManager returns a pointer to a new [GenericManager].
@@ -40,6 +44,32 @@ func (m *GenericManager[K, V, Q]) GetSuccessful() []V {
 	return result
 }
 
+// SetGate sets a [Gate] for pausing and resuming the processing of all (both
+// existing and future) managed queues.
+func (m *GenericManager[K, V, Q]) SetGate(g *Gate) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.gate = g
+
+	for _, q := range m.queues {
+		q.setGate(g)
+	}
+}
+
+// SetErrorFunc sets a function that is called for every error recorded for an
+// item of all (both existing and future) managed queues, as it is recorded.
+func (m *GenericManager[K, V, Q]) SetErrorFunc(errFunc func(item V, err error)) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.errFunc = errFunc
+
+	for _, q := range m.queues {
+		q.setErrorFunc(errFunc)
+	}
+}
+
 // Enqueue bucketizes items into queues according to a getKeyFunc, creating new
 // queues as required using a newQueueFunc.
 func (m *GenericManager[K, V, Q]) Enqueue(item V, getKeyFunc func(V) K, newQueueFunc func() Q) {
@@ -51,6 +81,8 @@ func (m *GenericManager[K, V, Q]) Enqueue(item V, getKeyFunc func(V) K, newQueue
 	_, exists := m.queues[key]
 	if !exists {
 		m.queues[key] = newQueueFunc()
+		m.queues[key].setGate(m.gate)
+		m.queues[key].setErrorFunc(m.errFunc)
 	}
 
 	m.queues[key].Enqueue(item)
@@ -2,7 +2,9 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -13,7 +15,8 @@ const (
 	// DecisionSuccess is returned by a processFunc when an item was processed.
 	DecisionSuccess = 1
 
-	// DecisionSkipped is returned by a processFunc when an item was skipped.
+	// DecisionSkipped is returned by a processFunc when an item was skipped,
+	// usually together with the error that has led to it being skipped.
 	DecisionSkipped = 0
 
 	// DecisionRequeue is returned by a processFunc when an item needs
@@ -33,12 +36,17 @@ type GenericQueue[V comparable] struct {
 	items       []V
 	success     []V
 	skipped     []V
+	filtered    []V
+	errs        map[V]error
 	inProgress  map[V]struct{}
+	gate        *Gate
+	errFunc     func(item V, err error)
 }
 
 // NewGenericQueue returns a pointer to a new [GenericQueue].
 func NewGenericQueue[V comparable]() *GenericQueue[V] {
 	return &GenericQueue[V]{
+		errs:       make(map[V]error),
 		inProgress: make(map[V]struct{}),
 	}
 }
@@ -67,6 +75,102 @@ func (q *GenericQueue[V]) GetSuccessful() []V {
 	return result
 }
 
+// GetSkipped returns a copy of the internal slice holding all skipped items.
+func (q *GenericQueue[V]) GetSkipped() []V {
+	q.RLock()
+	defer q.RUnlock()
+
+	result := make([]V, len(q.skipped))
+	copy(result, q.skipped)
+
+	return result
+}
+
+// GetFiltered returns a copy of the internal slice holding all items that were
+// removed from the queue by pre- or post-processing (e.g. by a filter).
+func (q *GenericQueue[V]) GetFiltered() []V {
+	q.RLock()
+	defer q.RUnlock()
+
+	result := make([]V, len(q.filtered))
+	copy(result, q.filtered)
+
+	return result
+}
+
+// GetErrors returns a copy of the internal map holding the errors of all
+// processed items, as returned by the processFunc. A skipped item usually has
+// the error that has led to it being skipped, whereas a successful item can
+// have an error of a partial failure (e.g. of one of its subelements).
+func (q *GenericQueue[V]) GetErrors() map[V]error {
+	q.RLock()
+	defer q.RUnlock()
+
+	result := make(map[V]error, len(q.errs))
+	maps.Copy(result, q.errs)
+
+	return result
+}
+
+// SetError records an error for an item, joining it with any error already
+// recorded for the same item. The error function of the queue (if any) is
+// called for it.
+func (q *GenericQueue[V]) SetError(item V, err error) {
+	if err == nil {
+		return
+	}
+
+	q.Lock()
+	q.errs[item] = errors.Join(q.errs[item], err)
+	errFunc := q.errFunc
+	q.Unlock()
+
+	if errFunc != nil {
+		errFunc(item, err)
+	}
+}
+
+// setErrorFunc sets a function that is called for every error recorded for an
+// item of the queue (as it is recorded).
+func (q *GenericQueue[V]) setErrorFunc(errFunc func(item V, err error)) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.errFunc = errFunc
+}
+
+// setGate sets a [Gate] for pausing and resuming the processing of the queue.
+func (q *GenericQueue[V]) setGate(g *Gate) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.gate = g
+}
+
+// getGate returns the [Gate] of the queue (which may be nil).
+func (q *GenericQueue[V]) getGate() *Gate {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.gate
+}
+
+// GetItems returns a copy of the internal slice holding all yet unprocessed
+// (remaining) items.
+func (q *GenericQueue[V]) GetItems() []V {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.head >= len(q.items) {
+		return []V{}
+	}
+
+	result := make([]V, len(q.items)-q.head)
+	copy(result, q.items[q.head:])
+
+	return result
+}
+
 // Enqueue adds items to the queue.
 func (q *GenericQueue[V]) Enqueue(items ...V) {
 	q.Lock()
@@ -202,30 +306,25 @@ func (q *GenericQueue[V]) Progress() Progress {
 
 // DequeueAndProcess sequentially dequeues and processes items using the given
 // processFunc. An error is only returned in case of a context cancellation, the
-// processFunc is otherwise expected to return only an integer with the
-// processing function's decision for that item.
+// processFunc is otherwise expected to return an integer with the processing
+// function's decision for that item, together with the error that has led to
+// it (if any), which is recorded for the item (see [GenericQueue.GetErrors]).
 //
 // Possible decisions to be returned: [DecisionSuccess], [DecisionSkipped],
 // [DecisionRequeue].
-func (q *GenericQueue[V]) DequeueAndProcess(ctx context.Context, processFunc func(V) int) error {
-	for ctx.Err() != nil {
+func (q *GenericQueue[V]) DequeueAndProcess(ctx context.Context, processFunc func(V) (int, error)) error {
+	for ctx.Err() == nil {
+		if err := q.getGate().Wait(ctx); err != nil {
+			break
+		}
+
 		item, ok := q.Dequeue()
 		if !ok {
 			break
 		}
 
 		q.SetProcessing(item)
-
-		switch processFunc(item) {
-		case DecisionRequeue:
-			q.Enqueue(item)
-
-		case DecisionSkipped:
-			q.SetSkipped(item)
-
-		case DecisionSuccess:
-			q.SetSuccess(item)
-		}
+		q.decide(item, processFunc)
 	}
 
 	if ctx.Err() != nil {
@@ -237,8 +336,9 @@ func (q *GenericQueue[V]) DequeueAndProcess(ctx context.Context, processFunc fun
 
 // DequeueAndProcessConc concurrently dequeues and processes items using given
 // processFunc. An error is only returned in case of a context cancellation, the
-// processFunc is otherwise expected to return only an integer with the
-// processing function's decision for that item.
+// processFunc is otherwise expected to return an integer with the processing
+// function's decision for that item, together with the error that has led to
+// it (if any), which is recorded for the item (see [GenericQueue.GetErrors]).
 //
 // Possible decisions to be returned: [DecisionSuccess], [DecisionSkipped],
 // [DecisionRequeue].
@@ -246,7 +346,9 @@ func (q *GenericQueue[V]) DequeueAndProcess(ctx context.Context, processFunc fun
 // It is the responsibility of the processFunc to ensure thread-safety for
 // anything happening inside the processFunc, with the [GenericQueue] only
 // guaranteeing thread-safety for itself.
-func (q *GenericQueue[V]) DequeueAndProcessConc(ctx context.Context, maxWorkers int, processFunc func(V) int) error {
+//
+// While the queue's [Gate] is paused, no further items are dequeued.
+func (q *GenericQueue[V]) DequeueAndProcessConc(ctx context.Context, maxWorkers int, processFunc func(V) (int, error)) error {
 	var wg sync.WaitGroup
 
 	semaphore := make(chan struct{}, maxWorkers)
@@ -261,6 +363,12 @@ LOOP:
 		case semaphore <- struct{}{}:
 		}
 
+		if err := q.getGate().Wait(ctx); err != nil {
+			wg.Wait()
+
+			return fmt.Errorf("(queue-concproc) %w", err)
+		}
+
 		item, ok := q.Dequeue()
 		if !ok {
 			<-semaphore
@@ -274,17 +382,7 @@ LOOP:
 			defer func() { <-semaphore }()
 
 			q.SetProcessing(item)
-
-			switch processFunc(item) {
-			case DecisionRequeue:
-				q.Enqueue(item)
-
-			case DecisionSkipped:
-				q.SetSkipped(item)
-
-			case DecisionSuccess:
-				q.SetSuccess(item)
-			}
+			q.decide(item, processFunc)
 		}(item)
 	}
 
@@ -302,14 +400,53 @@ LOOP:
 	return nil
 }
 
+// decide processes an in-progress item using the given processFunc, acting on
+// its decision and recording its error (unless the item is requeued).
+func (q *GenericQueue[V]) decide(item V, processFunc func(V) (int, error)) {
+	decision, err := processFunc(item)
+
+	switch decision {
+	case DecisionRequeue:
+		q.Enqueue(item)
+
+		return
+
+	case DecisionSkipped:
+		q.SetSkipped(item)
+
+	case DecisionSuccess:
+		q.SetSuccess(item)
+	}
+
+	q.SetError(item, err)
+}
+
+// Reorder reorders all yet unprocessed queue items using the given orderFunc,
+// which receives the items and returns them in their new order.
+func (q *GenericQueue[V]) Reorder(orderFunc func(items []V) []V) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.head >= len(q.items) {
+		return
+	}
+
+	remaining := append([]V{}, q.items[q.head:]...)
+	q.items = append(q.items[:q.head], orderFunc(remaining)...)
+}
+
 // PreProcess runs a [schema.Pipeline]'s contained pre-processors on all yet
-// unprocessed queue items.
+// unprocessed queue items. Items removed by them are recorded as filtered.
 //
 // If the queue is being operated on concurrently, sorting functions should not
 // be used as pre-processors. Instead, such functions should be post-processors
 // instead to guarantee that the order is preserved at the end of the queue.
 func (q *GenericQueue[V]) PreProcess(p schema.Pipeline[V]) bool {
+	q.Lock()
+	defer q.Unlock()
+
 	if items, ok := p.PreProcess(q.items); ok {
+		q.filtered = append(q.filtered, removedItems(q.items, items)...)
 		q.items = items
 
 		return true
@@ -319,9 +456,14 @@ func (q *GenericQueue[V]) PreProcess(p schema.Pipeline[V]) bool {
 }
 
 // PostProcess runs a [schema.Pipeline]'s contained post-processors on all
-// successfully processed queue items.
+// successfully processed queue items. Items removed by them are recorded as
+// filtered.
 func (q *GenericQueue[V]) PostProcess(p schema.Pipeline[V]) bool {
+	q.Lock()
+	defer q.Unlock()
+
 	if successitems, ok := p.PostProcess(q.success); ok {
+		q.filtered = append(q.filtered, removedItems(q.success, successitems)...)
 		q.success = successitems
 
 		return true
@@ -329,3 +471,22 @@ func (q *GenericQueue[V]) PostProcess(p schema.Pipeline[V]) bool {
 
 	return false
 }
+
+// removedItems returns all items that are contained in before, but no longer
+// in after.
+func removedItems[V comparable](before []V, after []V) []V {
+	kept := make(map[V]struct{}, len(after))
+	for _, item := range after {
+		kept[item] = struct{}{}
+	}
+
+	var removed []V
+
+	for _, item := range before {
+		if _, ok := kept[item]; !ok {
+			removed = append(removed, item)
+		}
+	}
+
+	return removed
+}
@@ -1,7 +1,6 @@
 package queue
 
 import (
-	"context"
 	"time"
 
 	"github.com/desertwitch/gover/internal/schema"
@@ -12,9 +11,10 @@ import (
 //
 // IOTargetQueue embeds a [GenericQueue].
 //
-// Beware that [IOTargetQueue] contained items can only be processed
+// Beware that [IOTargetQueue] contained items are meant to be processed
 // sequentially, in order not to operate concurrently within the same
-// destination target storage.
+// destination target storage. Concurrent processing should only be used for
+// targets that are known to handle concurrent writes well (e.g. SSD pools).
 //
 // The items contained within [IOTargetQueue] are [schema.Moveable].
 type IOTargetQueue struct {
@@ -23,6 +23,10 @@ type IOTargetQueue struct {
 	// bytesTransfered is the amount of bytes transferred for the
 	// [IOTargetQueue].
 	bytesTransfered uint64
+
+	// bytesCopied is the amount of bytes copied for the [IOTargetQueue] so
+	// far, including those of items that are still in progress (or failed).
+	bytesCopied uint64
 }
 
 // NewIOTargetQueue returns a pointer to a new [IOTargetQueue]. This method is
@@ -33,12 +37,6 @@ func NewIOTargetQueue() *IOTargetQueue {
 	}
 }
 
-// DequeueAndProcessConc is unsupported by [IOTargetQueue] and will result in a
-// panic when used.
-func (q *IOTargetQueue) DequeueAndProcessConc(ctx context.Context, maxWorkers int, processFunc func(*schema.Moveable) int) error { //nolint:revive
-	panic("An IOTargetQueue cannot be processed concurrently.")
-}
-
 // AddBytesTransfered adds given transferred bytes to the total amount
 // transferred for that [IOTargetQueue].
 func (q *IOTargetQueue) AddBytesTransfered(bytes uint64) {
@@ -48,7 +46,26 @@ func (q *IOTargetQueue) AddBytesTransfered(bytes uint64) {
 	q.bytesTransfered += bytes
 }
 
-// Progress returns the [Progress] of the [IOTargetQueue].
+// AddBytesCopied adds given copied bytes to the amount copied so far for that
+// [IOTargetQueue], as they are copied (for its throughput).
+func (q *IOTargetQueue) AddBytesCopied(bytes uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.bytesCopied += bytes
+}
+
+// GetBytesTransfered returns the total amount of bytes transferred for that
+// [IOTargetQueue].
+func (q *IOTargetQueue) GetBytesTransfered() uint64 {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.bytesTransfered
+}
+
+// Progress returns the [Progress] of the [IOTargetQueue], with its throughput
+// being that of the bytes copied so far (as throttled).
 func (q *IOTargetQueue) Progress() Progress {
 	qProgress := q.GenericQueue.Progress()
 
@@ -56,7 +73,7 @@ func (q *IOTargetQueue) Progress() Progress {
 		elapsed := time.Since(qProgress.StartTime)
 
 		q.RLock()
-		bytesPerSec := float64(q.bytesTransfered) / max(elapsed.Seconds(), 1)
+		bytesPerSec := float64(q.bytesCopied) / max(elapsed.Seconds(), 1)
 		q.RUnlock()
 
 		if bytesPerSec > 0 {
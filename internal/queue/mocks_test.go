@@ -176,3 +176,83 @@ func (_c *Mock_GenericQueueType_Progress_Call[V]) RunAndReturn(run func() Progre
 	_c.Call.Return(run)
 	return _c
 }
+
+// setErrorFunc provides a mock function for the type Mock_GenericQueueType
+func (_mock *Mock_GenericQueueType[V]) setErrorFunc(errFunc func(item V, err error)) {
+	_mock.Called(errFunc)
+	return
+}
+
+// Mock_GenericQueueType_setErrorFunc_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'setErrorFunc'
+type Mock_GenericQueueType_setErrorFunc_Call[V comparable] struct {
+	*mock.Call
+}
+
+// setErrorFunc is a helper method to define mock.On call
+//   - errFunc func(item V, err error)
+func (_e *Mock_GenericQueueType_Expecter[V]) setErrorFunc(errFunc interface{}) *Mock_GenericQueueType_setErrorFunc_Call[V] {
+	return &Mock_GenericQueueType_setErrorFunc_Call[V]{Call: _e.mock.On("setErrorFunc", errFunc)}
+}
+
+func (_c *Mock_GenericQueueType_setErrorFunc_Call[V]) Run(run func(errFunc func(item V, err error))) *Mock_GenericQueueType_setErrorFunc_Call[V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(item V, err error)
+		if args[0] != nil {
+			arg0 = args[0].(func(item V, err error))
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Mock_GenericQueueType_setErrorFunc_Call[V]) Return() *Mock_GenericQueueType_setErrorFunc_Call[V] {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Mock_GenericQueueType_setErrorFunc_Call[V]) RunAndReturn(run func(errFunc func(item V, err error))) *Mock_GenericQueueType_setErrorFunc_Call[V] {
+	_c.Run(run)
+	return _c
+}
+
+// setGate provides a mock function for the type Mock_GenericQueueType
+func (_mock *Mock_GenericQueueType[V]) setGate(g *Gate) {
+	_mock.Called(g)
+	return
+}
+
+// Mock_GenericQueueType_setGate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'setGate'
+type Mock_GenericQueueType_setGate_Call[V comparable] struct {
+	*mock.Call
+}
+
+// setGate is a helper method to define mock.On call
+//   - g *Gate
+func (_e *Mock_GenericQueueType_Expecter[V]) setGate(g interface{}) *Mock_GenericQueueType_setGate_Call[V] {
+	return &Mock_GenericQueueType_setGate_Call[V]{Call: _e.mock.On("setGate", g)}
+}
+
+func (_c *Mock_GenericQueueType_setGate_Call[V]) Run(run func(g *Gate)) *Mock_GenericQueueType_setGate_Call[V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Gate
+		if args[0] != nil {
+			arg0 = args[0].(*Gate)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *Mock_GenericQueueType_setGate_Call[V]) Return() *Mock_GenericQueueType_setGate_Call[V] {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Mock_GenericQueueType_setGate_Call[V]) RunAndReturn(run func(g *Gate)) *Mock_GenericQueueType_setGate_Call[V] {
+	_c.Run(run)
+	return _c
+}
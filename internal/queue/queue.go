@@ -23,15 +23,41 @@ type Manager struct {
 
 	// IOManager contains all sorted, allocated and validated [schema.Moveable].
 	IOManager *IOManager
+
+	// gate is the [Gate] shared by all managed queues for pausing/resuming.
+	gate *Gate
 }
 
 // NewManager returns a pointer to a new queue [Manager].
 func NewManager() *Manager {
-	return &Manager{
+	m := &Manager{
 		EnumerationManager: NewEnumerationManager(),
 		EvaluationManager:  NewEvaluationManager(),
 		IOManager:          NewIOManager(),
+		gate:               NewGate(),
 	}
+
+	m.EnumerationManager.SetGate(m.gate)
+	m.EvaluationManager.SetGate(m.gate)
+	m.IOManager.SetGate(m.gate)
+
+	return m
+}
+
+// Pause pauses the processing of all managed queues, letting only already
+// in-progress items finish.
+func (m *Manager) Pause() {
+	m.gate.Pause()
+}
+
+// Resume resumes the processing of all managed queues.
+func (m *Manager) Resume() {
+	m.gate.Resume()
+}
+
+// IsPaused returns whether the processing of all managed queues is paused.
+func (m *Manager) IsPaused() bool {
+	return m.gate.IsPaused()
 }
 
 // Progress holds information about the progress of a queue (or manager). It is
@@ -0,0 +1,253 @@
+package report
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+const (
+	// ClassOther is the error class of all errors not matching any of the
+	// known error classes.
+	ClassOther = "other"
+)
+
+// ErrorClass is a known error class, as matched using [errors.Is]. The known
+// error classes are given to a [Collector] by the application, so that the
+// package does not need to know about the errors of all other packages.
+type ErrorClass struct {
+	// Name is the name of the error class, as it is reported.
+	Name string
+
+	// Err is the (sentinel) error that belongs to the error class.
+	Err error
+
+	// Filter is whether errors of the class are the reasons of a policy (such
+	// as a filter) for intentionally leaving out an item. These are counted as
+	// filtered items, not as errors.
+	Filter bool
+}
+
+// occurrence is a single classified error or filtered item of a run.
+type occurrence struct {
+	stage    string
+	share    string
+	target   string
+	class    string
+	filtered bool
+	skipped  bool
+}
+
+// failure is a queue that has failed as a whole, as recorded by a [Collector].
+type failure struct {
+	stage string
+	queue string
+}
+
+// Collector records the errors of a run that do not belong to any item of a
+// queue (such as those of failed hooks) and the queues that have failed as a
+// whole, so that both can be included in a [Report] (along with the errors of
+// the items, as recorded by their queues). It classifies all errors by the
+// known error classes. It is safe for concurrent use.
+type Collector struct {
+	sync.RWMutex
+
+	classes     []ErrorClass
+	occurrences []occurrence
+	failures    []failure
+}
+
+// NewCollector returns a pointer to a new [Collector], classifying errors by
+// the given [ErrorClass] (in the order they are matched).
+func NewCollector(classes []ErrorClass) *Collector {
+	return &Collector{
+		classes: classes,
+	}
+}
+
+// Classify returns the name of the known error class an error belongs to, or
+// [ClassOther] if it does not match any of the known error classes.
+func (c *Collector) Classify(err error) string {
+	class, _ := c.classify(err)
+
+	return class
+}
+
+// AddError records an error of a stage that does not belong to any item of a
+// queue, attributing it to a [schema.Share] and target [schema.Storage]. Any of
+// these can be empty, with an error of no stage only counting towards the
+// errors of the [Report] as a whole.
+func (c *Collector) AddError(stage string, share string, target string, err error) {
+	if err == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, e := range splitErrors(err) {
+		class, _ := c.classify(e)
+		c.occurrences = append(c.occurrences, occurrence{
+			stage:  stage,
+			share:  share,
+			target: target,
+			class:  class,
+		})
+	}
+}
+
+// AddFailure records a queue of a stage that has failed as a whole, together
+// with the error it has failed with (if any).
+func (c *Collector) AddFailure(stage string, queue string, err error) {
+	c.AddError(stage, "", "", err)
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.failures = append(c.failures, failure{stage: stage, queue: queue})
+}
+
+// classify returns the name of the known error class an error belongs to (or
+// [ClassOther]) and whether it is the class of a filter.
+func (c *Collector) classify(err error) (string, bool) {
+	for _, class := range c.classes {
+		if errors.Is(err, class.Err) {
+			return class.Name, class.Filter
+		}
+	}
+
+	return ClassOther, false
+}
+
+// collected returns a copy of all recorded occurrences.
+func (c *Collector) collected() occurrences {
+	c.RLock()
+	defer c.RUnlock()
+
+	return append(occurrences{}, c.occurrences...)
+}
+
+// failedQueues returns the names of all failed queues of a stage.
+func (c *Collector) failedQueues(stage string) []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	var queues []string
+
+	for _, f := range c.failures {
+		if f.stage == stage {
+			queues = append(queues, f.queue)
+		}
+	}
+
+	sort.Strings(queues)
+
+	return queues
+}
+
+// itemQueue defines the methods a queue needs to have for the results of its
+// items to be collected.
+type itemQueue[V comparable] interface {
+	GetSkipped() []V
+	GetFiltered() []V
+	GetErrors() map[V]error
+}
+
+// collectItems returns the occurrences of all items of a queue of a stage (and
+// target, if any): the classified errors of its processed items, with those of
+// a filter counting as filtered (and skipped, if their item was skipped), and
+// the items removed from it by pre- or post-processing as filtered.
+func collectItems[V comparable](c *Collector, stage string, target string, q itemQueue[V], shareOf func(V) string) occurrences {
+	var collected occurrences
+
+	skipped := make(map[V]struct{})
+	for _, item := range q.GetSkipped() {
+		skipped[item] = struct{}{}
+	}
+
+	for item, err := range q.GetErrors() {
+		_, isSkipped := skipped[item]
+
+		for _, e := range splitErrors(err) {
+			class, filter := c.classify(e)
+			collected = append(collected, occurrence{
+				stage:    stage,
+				share:    shareOf(item),
+				target:   target,
+				class:    class,
+				filtered: filter,
+				skipped:  filter && isSkipped,
+			})
+
+			if filter {
+				// A skipped item counts only once as filtered.
+				isSkipped = false
+			}
+		}
+	}
+
+	for _, item := range q.GetFiltered() {
+		collected = append(collected, occurrence{
+			stage:    stage,
+			share:    shareOf(item),
+			target:   target,
+			filtered: true,
+		})
+	}
+
+	return collected
+}
+
+// splitErrors returns the individual errors of a joined error (as joined with
+// [errors.Join]), or otherwise just the error itself.
+func splitErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
+
+// occurrences are the classified errors and filtered items of a run.
+type occurrences []occurrence
+
+// countErrors returns the [ErrorCount] of all classified errors matching the
+// given filter function, sorted by their class.
+func (occs occurrences) countErrors(filterFunc func(o occurrence) bool) []*ErrorCount {
+	counts := make(map[string]int)
+
+	for _, o := range occs {
+		if !o.filtered && filterFunc(o) {
+			counts[o.class]++
+		}
+	}
+
+	errorCounts := make([]*ErrorCount, 0, len(counts))
+	for class, count := range counts {
+		errorCounts = append(errorCounts, &ErrorCount{Class: class, Count: count})
+	}
+
+	sort.Slice(errorCounts, func(i, j int) bool {
+		return errorCounts[i].Class < errorCounts[j].Class
+	})
+
+	return errorCounts
+}
+
+// countFiltered returns the amount of filtered items matching the given filter
+// function, and how many of these were skipped by their queue.
+func (occs occurrences) countFiltered(filterFunc func(o occurrence) bool) (int, int) {
+	var filtered, skipped int
+
+	for _, o := range occs {
+		if o.filtered && filterFunc(o) {
+			filtered++
+
+			if o.skipped {
+				skipped++
+			}
+		}
+	}
+
+	return filtered, skipped
+}
@@ -0,0 +1,9 @@
+package report
+
+import "errors"
+
+var (
+	// ErrUnknownFormat occurs when a [Report] is requested to be written in an
+	// unknown format.
+	ErrUnknownFormat = errors.New("unknown report format")
+)
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// FormatJSON is the format for writing a [Report] as machine-readable
+	// (indented) JSON.
+	FormatJSON = "json"
+
+	// FormatText is the format for writing a [Report] as human-readable text.
+	FormatText = "text"
+
+	// tableMinWidth is the minimal cell width of the human-readable table.
+	tableMinWidth = 0
+
+	// tableTabWidth is the tab width of the human-readable table.
+	tableTabWidth = 8
+
+	// tablePadding is the cell padding of the human-readable table.
+	tablePadding = 2
+)
+
+// Write writes the [Report] in the given format. An [ErrUnknownFormat] is
+// returned for a format other than [FormatJSON] or [FormatText].
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+
+	case FormatText:
+		return r.WriteText(w)
+
+	default:
+		return fmt.Errorf("(report) %w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// WriteJSON writes the [Report] as machine-readable (indented) JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("(report-json) failed to encode: %w", err)
+	}
+
+	return nil
+}
+
+// WriteText writes the [Report] as human-readable text, with a table of the
+// summaries for each stage.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, tableMinWidth, tableTabWidth, tablePadding, ' ', 0)
+
+	fmt.Fprintf(tw, "COMMAND: %s\n", r.Command)
+	fmt.Fprintf(tw, "STARTED: %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(tw, "FINISHED: %s (%s)\n", r.FinishedAt.Format(time.RFC3339), r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	fmt.Fprintf(tw, "OUTCOME: %s\n", r.Outcome)
+
+	if r.Fatal != "" {
+		fmt.Fprintf(tw, "FATAL: %s\n", r.Fatal)
+	}
+
+	fmt.Fprintln(tw)
+
+	for _, s := range r.Stages {
+		fmt.Fprintf(tw, "STAGE: %s\n", s.Name)
+		fmt.Fprintln(tw, "NAME\tITEMS\tSUCCESS\tSKIPPED\tFILTERED\tBYTES\tERRORS")
+		writeSummary(tw, "*", &s.Summary)
+
+		for _, share := range s.Shares {
+			writeSummary(tw, "share:"+share.Name, share)
+		}
+
+		for _, target := range s.Targets {
+			writeSummary(tw, "target:"+target.Name, target)
+		}
+
+		if len(s.FailedQueues) > 0 {
+			fmt.Fprintf(tw, "FAILED: %s\n", strings.Join(s.FailedQueues, ", "))
+		}
+
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintf(tw, "TOTAL: %d skipped, %d filtered, %d errors\n", r.TotalSkipped(), r.TotalFiltered(), r.TotalErrors())
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(tw, "ERRORS: %s\n", formatErrors(r.Errors))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("(report-text) failed to flush: %w", err)
+	}
+
+	return nil
+}
+
+// writeSummary writes a [Summary] as a row of the human-readable table.
+func writeSummary(w io.Writer, name string, s *Summary) {
+	if s.Failed {
+		name += " (failed)"
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t%s\n",
+		name, s.Items, s.Success, s.Skipped, s.Filtered, humanize.IBytes(s.Bytes), formatErrors(s.Errors))
+}
+
+// formatErrors returns the human-readable representation of [ErrorCount].
+func formatErrors(errs []*ErrorCount) string {
+	if len(errs) == 0 {
+		return "-"
+	}
+
+	parts := make([]string, 0, len(errs))
+	for _, e := range errs {
+		parts = append(parts, fmt.Sprintf("%s=%d", e.Class, e.Count))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,405 @@
+// Package report implements structures and routines for summarizing the
+// outcome of a run, aggregating the results of all stages, [schema.Share] and
+// target [schema.Storage] together with the classes of all encountered errors.
+//
+// A report can be written in machine- and human-readable formats, and its
+// [Outcome] is meant to be turned into a meaningful exit code.
+package report
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/desertwitch/gover/internal/progress"
+	"github.com/desertwitch/gover/internal/queue"
+	"github.com/desertwitch/gover/internal/schema"
+)
+
+// Outcome is the overall outcome of a run.
+type Outcome string
+
+const (
+	// OutcomeSuccess is the [Outcome] of a run where all items were processed
+	// successfully and no errors occurred.
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomePartial is the [Outcome] of a run that has completed, but with
+	// items that were skipped, queues that failed or errors that occurred.
+	// Items that were intentionally filtered do not make a run partial.
+	OutcomePartial Outcome = "partial"
+
+	// OutcomeFatal is the [Outcome] of a run that has not completed.
+	OutcomeFatal Outcome = "fatal"
+)
+
+// Report is the principal structure summarizing a run. It is meant to be
+// passed by reference (pointer).
+type Report struct {
+	// Command is the command that was run.
+	Command string `json:"command"`
+
+	// StartedAt is the time the run was started at.
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is the time the run was finished at.
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// Outcome is the overall [Outcome] of the run.
+	Outcome Outcome `json:"outcome"`
+
+	// Fatal is the error that has caused an [OutcomeFatal] (if any).
+	Fatal string `json:"fatal,omitempty"`
+
+	// Stages are the [Stage] summaries, in the order of their processing.
+	Stages []*Stage `json:"stages"`
+
+	// Errors are the counts of all classified errors across all stages.
+	Errors []*ErrorCount `json:"errors,omitempty"`
+
+	// filteredSkipped is the amount of skipped items that were filtered.
+	filteredSkipped int
+}
+
+// Stage summarizes one stage of a run, together with its [schema.Share] and
+// (for the IO stage) target [schema.Storage].
+type Stage struct {
+	Summary
+
+	// FailedQueues are the names of the queues that failed as a whole.
+	FailedQueues []string `json:"failedQueues,omitempty"`
+
+	// Shares are the summaries per [schema.Share], sorted by their name.
+	Shares []*Summary `json:"shares,omitempty"`
+
+	// Targets are the summaries per target [schema.Storage], sorted by their
+	// name.
+	Targets []*Summary `json:"targets,omitempty"`
+}
+
+// Summary holds the counts of a stage, [schema.Share] or [schema.Storage].
+// The filtered items are those intentionally left out by a policy (such as a
+// filter), which also count as skipped if they were skipped by their queue.
+type Summary struct {
+	Name     string        `json:"name"`
+	Items    int           `json:"items"`
+	Success  int           `json:"success"`
+	Skipped  int           `json:"skipped"`
+	Filtered int           `json:"filtered,omitempty"`
+	Bytes    uint64        `json:"bytes,omitempty"`
+	Failed   bool          `json:"failed,omitempty"`
+	Errors   []*ErrorCount `json:"errors,omitempty"`
+}
+
+// ErrorCount is the amount of errors of one specific error class.
+type ErrorCount struct {
+	Class string `json:"class"`
+	Count int    `json:"count"`
+}
+
+// New returns a pointer to a new [Report] for a run of a command, built from
+// the results of the items of the [queue.Manager] (as recorded by its queues)
+// and the [Collector] of that run. A non-nil runErr results in an
+// [OutcomeFatal].
+func New(command string, startedAt time.Time, queueManager *queue.Manager, collector *Collector, runErr error) *Report {
+	snapshot := progress.Collect(queueManager)
+	collected := append(collector.collected(), collectQueues(collector, queueManager)...)
+
+	r := &Report{
+		Command:    command,
+		StartedAt:  startedAt,
+		FinishedAt: snapshot.Time,
+		Errors:     collected.countErrors(func(occurrence) bool { return true }),
+	}
+
+	enumeration := newStage(snapshot.Enumeration, collector, collected)
+	enumeration.Shares = collectShares(collected, progress.StageEnumeration, enumerationShares(queueManager))
+
+	evaluation := newStage(snapshot.Evaluation, collector, collected)
+	evaluation.Shares = collectShares(collected, progress.StageEvaluation, moveableShares(queueManager.EvaluationManager.GetQueues()))
+
+	ioStage := newStage(snapshot.IO, collector, collected)
+	ioStage.Bytes = snapshot.IO.BytesTransferred
+	ioStage.Shares = collectShares(collected, progress.StageIO, moveableShares(queueManager.IOManager.GetQueues()))
+
+	for _, record := range snapshot.Queues {
+		if record.Stage != progress.StageIO {
+			continue
+		}
+
+		target := newSummary(record.Queue, record)
+		target.Bytes = record.BytesTransferred
+		target.Failed = slices.Contains(ioStage.FailedQueues, record.Queue)
+		target.Errors = collected.countErrors(func(o occurrence) bool {
+			return o.stage == progress.StageIO && o.target == record.Queue
+		})
+		target.Filtered, _ = collected.countFiltered(func(o occurrence) bool {
+			return o.stage == progress.StageIO && o.target == record.Queue
+		})
+
+		ioStage.Targets = append(ioStage.Targets, target)
+	}
+
+	for _, share := range evaluation.Shares {
+		share.Failed = slices.Contains(evaluation.FailedQueues, share.Name)
+		evaluation.Bytes += share.Bytes
+	}
+
+	r.Stages = []*Stage{enumeration, evaluation, ioStage}
+	_, r.filteredSkipped = collected.countFiltered(func(occurrence) bool { return true })
+	r.Outcome = r.outcome(runErr)
+
+	if runErr != nil {
+		r.Fatal = runErr.Error()
+	}
+
+	return r
+}
+
+// Stage returns the [Stage] of the given name (e.g. [progress.StageIO]), or nil
+// if the [Report] has no such [Stage].
+func (r *Report) Stage(name string) *Stage {
+	for _, s := range r.Stages {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// TotalSkipped returns the amount of skipped items across all stages.
+func (r *Report) TotalSkipped() int {
+	var total int
+
+	for _, s := range r.Stages {
+		total += s.Skipped
+	}
+
+	return total
+}
+
+// TotalFiltered returns the amount of filtered items across all stages.
+func (r *Report) TotalFiltered() int {
+	var total int
+
+	for _, s := range r.Stages {
+		total += s.Filtered
+	}
+
+	return total
+}
+
+// TotalErrors returns the amount of classified errors across all stages.
+func (r *Report) TotalErrors() int {
+	var total int
+
+	for _, e := range r.Errors {
+		total += e.Count
+	}
+
+	return total
+}
+
+// outcome returns the [Outcome] of the [Report].
+func (r *Report) outcome(runErr error) Outcome {
+	if runErr != nil {
+		return OutcomeFatal
+	}
+
+	if r.TotalSkipped() > r.filteredSkipped || r.TotalErrors() > 0 {
+		return OutcomePartial
+	}
+
+	for _, s := range r.Stages {
+		if len(s.FailedQueues) > 0 {
+			return OutcomePartial
+		}
+	}
+
+	return OutcomeSuccess
+}
+
+// newStage returns a pointer to a new [Stage] for the [progress.Record] of a
+// manager.
+func newStage(record progress.Record, collector *Collector, collected occurrences) *Stage {
+	s := &Stage{
+		Summary:      *newSummary(record.Stage, record),
+		FailedQueues: collector.failedQueues(record.Stage),
+	}
+
+	s.Errors = collected.countErrors(func(o occurrence) bool {
+		return o.stage == record.Stage
+	})
+	s.Filtered, _ = collected.countFiltered(func(o occurrence) bool {
+		return o.stage == record.Stage
+	})
+
+	return s
+}
+
+// newSummary returns a pointer to a new [Summary] for a [progress.Record].
+func newSummary(name string, record progress.Record) *Summary {
+	return &Summary{
+		Name:    name,
+		Items:   record.TotalItems,
+		Success: record.SuccessItems,
+		Skipped: record.SkippedItems,
+	}
+}
+
+// shareCounts holds the counts of a [schema.Share] within one stage.
+type shareCounts struct {
+	items   int
+	success int
+	skipped int
+	bytes   uint64
+}
+
+// collectShares returns a [Summary] for each of the given [shareCounts] of a
+// stage, sorted by the names of their [schema.Share].
+func collectShares(collected occurrences, stage string, counts map[string]*shareCounts) []*Summary {
+	summaries := make([]*Summary, 0, len(counts))
+
+	for name, c := range counts {
+		inShare := func(o occurrence) bool {
+			return o.stage == stage && o.share == name
+		}
+
+		filtered, _ := collected.countFiltered(inShare)
+
+		summaries = append(summaries, &Summary{
+			Name:     name,
+			Items:    c.items,
+			Success:  c.success,
+			Skipped:  c.skipped,
+			Filtered: filtered,
+			Bytes:    c.bytes,
+			Errors:   collected.countErrors(inShare),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries
+}
+
+// collectQueues returns the occurrences of the items of all queues of the
+// [queue.Manager], attributed to their stage, [schema.Share] and (for the IO
+// stage) target [schema.Storage].
+func collectQueues(collector *Collector, queueManager *queue.Manager) occurrences {
+	var collected occurrences
+
+	for _, q := range queueManager.EnumerationManager.GetQueues() {
+		collected = append(collected, collectItems(collector, progress.StageEnumeration, "", q, func(t *queue.EnumerationTask) string {
+			return shareName(t.Share)
+		})...)
+	}
+
+	for _, q := range queueManager.EvaluationManager.GetQueues() {
+		collected = append(collected, collectItems(collector, progress.StageEvaluation, "", q, moveableShare)...)
+	}
+
+	for target, q := range queueManager.IOManager.GetQueues() {
+		collected = append(collected, collectItems(collector, progress.StageIO, target.GetName(), q, moveableShare)...)
+	}
+
+	return collected
+}
+
+// moveableShare returns the name of the [schema.Share] of a [schema.Moveable].
+func moveableShare(m *schema.Moveable) string {
+	return shareName(m.Share)
+}
+
+// shareName returns the name of a [schema.Share], or an empty name for nil.
+func shareName(share schema.Share) string {
+	if share == nil {
+		return ""
+	}
+
+	return share.GetName()
+}
+
+// enumerationShares returns the [shareCounts] of all [queue.EnumerationTask]
+// by the names of their [schema.Share].
+func enumerationShares(queueManager *queue.Manager) map[string]*shareCounts {
+	counts := make(map[string]*shareCounts)
+
+	for _, q := range queueManager.EnumerationManager.GetQueues() {
+		countByShare(counts, q.GetItems(), q.GetSuccessful(), q.GetSkipped(), func(t *queue.EnumerationTask) (schema.Share, uint64) {
+			return t.Share, 0
+		})
+	}
+
+	return counts
+}
+
+// moveableShares returns the [shareCounts] of all [schema.Moveable] of the
+// given queues by the names of their [schema.Share]. The bytes are the sum of
+// the sizes of all successful [schema.Moveable].
+func moveableShares[K comparable, Q moveableQueue](queues map[K]Q) map[string]*shareCounts {
+	counts := make(map[string]*shareCounts)
+
+	for _, q := range queues {
+		countByShare(counts, q.GetItems(), q.GetSuccessful(), q.GetSkipped(), func(m *schema.Moveable) (schema.Share, uint64) {
+			if m.Metadata == nil {
+				return m.Share, 0
+			}
+
+			return m.Share, m.Metadata.Size
+		})
+	}
+
+	return counts
+}
+
+// moveableQueue defines the methods a queue of [schema.Moveable] needs to have.
+type moveableQueue interface {
+	GetItems() []*schema.Moveable
+	GetSuccessful() []*schema.Moveable
+	GetSkipped() []*schema.Moveable
+}
+
+// countByShare adds remaining, successful and skipped items to the
+// [shareCounts] of their [schema.Share], as returned by the getFunc (along with
+// their size).
+func countByShare[V any](counts map[string]*shareCounts, remaining []V, successful []V, skipped []V, getFunc func(V) (schema.Share, uint64)) {
+	get := func(item V) (*shareCounts, uint64) {
+		share, size := getFunc(item)
+		if share == nil {
+			return nil, 0
+		}
+
+		c, exists := counts[share.GetName()]
+		if !exists {
+			c = &shareCounts{}
+			counts[share.GetName()] = c
+		}
+
+		return c, size
+	}
+
+	for _, item := range remaining {
+		if c, _ := get(item); c != nil {
+			c.items++
+		}
+	}
+
+	for _, item := range successful {
+		if c, size := get(item); c != nil {
+			c.items++
+			c.success++
+			c.bytes += size
+		}
+	}
+
+	for _, item := range skipped {
+		if c, _ := get(item); c != nil {
+			c.items++
+			c.skipped++
+		}
+	}
+}
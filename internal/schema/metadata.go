@@ -10,6 +10,7 @@ type Metadata struct {
 	GID        uint32
 	AccessedAt unix.Timespec
 	ModifiedAt unix.Timespec
+	ChangedAt  unix.Timespec
 	Size       uint64
 	IsDir      bool
 	IsSymlink  bool
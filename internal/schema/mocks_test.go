@@ -319,18 +319,18 @@ func (_c *Mock_Pipeline_PreProcess_Call[T]) RunAndReturn(run func(items []T) ([]
 }
 
 // Process provides a mock function for the type Mock_Pipeline
-func (_mock *Mock_Pipeline[T]) Process(item T) bool {
+func (_mock *Mock_Pipeline[T]) Process(item T) error {
 	ret := _mock.Called(item)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Process")
 	}
 
-	var r0 bool
-	if returnFunc, ok := ret.Get(0).(func(T) bool); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(T) error); ok {
 		r0 = returnFunc(item)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
@@ -359,12 +359,12 @@ func (_c *Mock_Pipeline_Process_Call[T]) Run(run func(item T)) *Mock_Pipeline_Pr
 	return _c
 }
 
-func (_c *Mock_Pipeline_Process_Call[T]) Return(b bool) *Mock_Pipeline_Process_Call[T] {
-	_c.Call.Return(b)
+func (_c *Mock_Pipeline_Process_Call[T]) Return(err error) *Mock_Pipeline_Process_Call[T] {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *Mock_Pipeline_Process_Call[T]) RunAndReturn(run func(item T) bool) *Mock_Pipeline_Process_Call[T] {
+func (_c *Mock_Pipeline_Process_Call[T]) RunAndReturn(run func(item T) error) *Mock_Pipeline_Process_Call[T] {
 	_c.Call.Return(run)
 	return _c
 }
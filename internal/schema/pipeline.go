@@ -2,16 +2,17 @@ package schema
 
 // Processor is a function that processes [T] as part of a [Pipeline].
 //
-// It should return a success boolean, any output the user needs to be aware of
-// should be made with [Slog] calls, as no error type can be returned through
-// the processor itself.
+// It should return nil on success, or otherwise the error that is the reason
+// for the failure (e.g. for a filter, why the item was filtered), which is
+// recorded for the item by its queue. Any further output the user needs to be
+// aware of should be made with [Slog] calls.
 //
 // During execution the pipeline should exit on the first failed processor, so
-// "false" should be used only where pipeline failure is intended. The pipeline
-// itself should not be context-aware, rather the Processor can capture a
-// context and handle itself its cancellation by returning "false" where and
+// an error should be returned only where pipeline failure is intended. The
+// pipeline itself should not be context-aware, rather the Processor can capture
+// a context and handle itself its cancellation by returning an error where and
 // when early exit from the overall pipeline is warranted and wanted.
-type Processor[T any] func(item T) bool
+type Processor[T any] func(item T) error
 
 // BatchProcessor is a function that processes a slice of [T] as part of a
 // [Pipeline]. During execution, only copies of the original slice are given to
@@ -52,8 +53,9 @@ type Pipeline[T any] interface {
 	// slice and also itself return a copy of the given slice (manipulated).
 	PreProcess(items []T) ([]T, bool)
 
-	// Process runs all [Processor] processors on the given [T].
-	Process(item T) bool
+	// Process runs all [Processor] processors on the given [T], returning the
+	// error of the first failed [Processor] (if any).
+	Process(item T) error
 
 	// PostProcess runs all [BatchProcessor] post-processors on the given slice
 	// of [T]. The function must be designed to operate on copies of the given
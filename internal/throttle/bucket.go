@@ -0,0 +1,77 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket, holding up to one second of its rate in tokens
+// (bytes). Taking more tokens than it holds puts the [bucket] into debt, which
+// is paid off (by waiting) before any further tokens can be taken.
+type bucket struct {
+	sync.Mutex
+
+	// The rate in bytes per second (0 meaning no limit).
+	rate float64
+
+	// The available tokens, being negative while in debt.
+	tokens float64
+
+	// The time the tokens were last refilled.
+	last time.Time
+}
+
+// newBucket returns a pointer to a new (full) [bucket] of the given rate.
+func newBucket(rate uint64) *bucket {
+	return &bucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// setRate changes the rate of the [bucket], keeping any debt (which is then
+// paid off at the new rate).
+func (b *bucket) setRate(rate uint64) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill(time.Now())
+	b.rate = float64(rate)
+	b.tokens = min(b.tokens, b.rate)
+}
+
+// take takes an amount of tokens (bytes) from the [bucket], returning how long
+// to wait until they would have been available.
+func (b *bucket) take(n int) time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refill adds the tokens accumulated since the last refill, up to one second
+// of the rate.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+
+	if b.rate <= 0 {
+		b.tokens = 0
+
+		return
+	}
+
+	b.tokens = min(b.tokens+elapsed*b.rate, b.rate)
+}
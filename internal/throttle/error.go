@@ -0,0 +1,8 @@
+package throttle
+
+import "errors"
+
+var (
+	// ErrInvalidRate occurs when a bandwidth limit is malformed.
+	ErrInvalidRate = errors.New("invalid bandwidth limit")
+)
@@ -0,0 +1,171 @@
+// Package throttle implements token-bucket rate limiting of the bandwidth of
+// the IO, with a global limit and limits per source and target
+// [schema.Storage]. All limits can be changed at runtime, taking effect on the
+// transfers already in progress.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Limits are bandwidth limits in bytes per second, with a limit of 0 meaning
+// no limit. The source and target [schema.Storage] are keyed by their names.
+type Limits struct {
+	Global  uint64            `json:"global"`
+	Sources map[string]uint64 `json:"sources"`
+	Targets map[string]uint64 `json:"targets"`
+}
+
+// Limiter is the principal implementation of the bandwidth throttling. It is
+// safe for concurrent use, with all transfers sharing the same buckets.
+type Limiter struct {
+	sync.RWMutex
+
+	limits  Limits
+	global  *bucket
+	sources map[string]*bucket
+	targets map[string]*bucket
+}
+
+// NewLimiter returns a pointer to a new [Limiter] with the given [Limits].
+func NewLimiter(limits Limits) *Limiter {
+	l := &Limiter{
+		global:  newBucket(0),
+		sources: make(map[string]*bucket),
+		targets: make(map[string]*bucket),
+	}
+	l.SetLimits(limits)
+
+	return l
+}
+
+// Limits returns (a copy of) the current [Limits] of the [Limiter].
+func (l *Limiter) Limits() Limits {
+	l.RLock()
+	defer l.RUnlock()
+
+	return Limits{
+		Global:  l.limits.Global,
+		Sources: maps.Clone(l.limits.Sources),
+		Targets: maps.Clone(l.limits.Targets),
+	}
+}
+
+// SetLimits replaces the [Limits] of the [Limiter], with any source or target
+// no longer included in them no longer being limited.
+func (l *Limiter) SetLimits(limits Limits) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.limits = Limits{
+		Global:  limits.Global,
+		Sources: maps.Clone(limits.Sources),
+		Targets: maps.Clone(limits.Targets),
+	}
+
+	l.global.setRate(limits.Global)
+	setBucketRates(l.sources, limits.Sources)
+	setBucketRates(l.targets, limits.Targets)
+}
+
+// Wait takes an amount of bytes (as just transferred from a source to a
+// target) from the applicable buckets, waiting until all of them allow for
+// it. An error is only returned in case of a context cancellation.
+func (l *Limiter) Wait(ctx context.Context, source string, target string, n int) error {
+	l.RLock()
+	buckets := []*bucket{l.global, l.sources[source], l.targets[target]}
+	l.RUnlock()
+
+	var delay time.Duration
+	for _, b := range buckets {
+		if b != nil {
+			delay = max(delay, b.take(n))
+		}
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("(throttle) %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// setBucketRates sets the rates of the buckets (map[name]bucket) from the
+// limits (map[name]rate), adding missing and removing unlimited buckets.
+func setBucketRates(buckets map[string]*bucket, limits map[string]uint64) {
+	for name, b := range buckets {
+		if _, exists := limits[name]; !exists {
+			b.setRate(0)
+			delete(buckets, name)
+		}
+	}
+
+	for name, rate := range limits {
+		if b, exists := buckets[name]; exists {
+			b.setRate(rate)
+		} else {
+			buckets[name] = newBucket(rate)
+		}
+	}
+}
+
+// ParseRate parses a bandwidth limit given as a size per second (e.g. "50MiB",
+// "50MiB/s" or "0" for no limit) into bytes per second. An [ErrInvalidRate] is
+// returned for a malformed size.
+func ParseRate(s string) (uint64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "/s")
+	if s == "" {
+		return 0, nil
+	}
+
+	rate, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("(throttle) %w: %q", ErrInvalidRate, s)
+	}
+
+	return rate, nil
+}
+
+// ParseRates parses bandwidth limits given as comma-separated "name=rate"
+// pairs (e.g. "cache=100MiB,disk1=20MiB") into a map (map[name]rate), see
+// [ParseRate] for the rates. An [ErrInvalidRate] is returned for malformed
+// pairs.
+func ParseRates(s string) (map[string]uint64, error) {
+	rates := make(map[string]uint64)
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("(throttle) %w: %q", ErrInvalidRate, pair)
+		}
+
+		rate, err := ParseRate(value)
+		if err != nil {
+			return nil, err
+		}
+
+		rates[strings.TrimSpace(name)] = rate
+	}
+
+	return rates, nil
+}
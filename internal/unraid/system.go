@@ -3,6 +3,8 @@ package unraid
 import (
 	"fmt"
 	"maps"
+
+	"github.com/desertwitch/gover/internal/schema"
 )
 
 // System is the top-level representation of an Unraid system.
@@ -36,6 +38,24 @@ func (s *System) GetShares() map[string]*Share {
 	return shares
 }
 
+// GetStorages returns a map (map[storageName]schema.Storage) with all [Disk]
+// of the [Array] and all [Pool], implementing [schema.Storage].
+func (s *System) GetStorages() map[string]schema.Storage {
+	storages := make(map[string]schema.Storage)
+
+	if s.Array != nil {
+		for name, disk := range s.Array.Disks {
+			storages[name] = disk
+		}
+	}
+
+	for name, pool := range s.Pools {
+		storages[name] = pool
+	}
+
+	return storages
+}
+
 // EstablishSystem returns a pointer to an established Unraid [System]. It is
 // the principal method to retrieve all information from the Unraid system.
 func (u *Handler) EstablishSystem() (*System, error) {
@@ -9,8 +9,8 @@ import (
 )
 
 // ValidateMoveable is the principal function to validate a [schema.Moveable]
-// and its subelements.
-func ValidateMoveable(m *schema.Moveable) bool {
+// and its subelements, returning the error of the first failed validation.
+func ValidateMoveable(m *schema.Moveable) error {
 	if err := validateMoveable(m); err != nil {
 		slog.Warn("Skipped job: failed pre-move validation",
 			"err", err,
@@ -18,11 +18,9 @@ func ValidateMoveable(m *schema.Moveable) bool {
 			"share", m.Share.GetName(),
 		)
 
-		return false
+		return err
 	}
 
-	hardLinkFailure := false
-
 	for _, h := range m.Hardlinks {
 		if err := validateMoveable(h); err != nil {
 			slog.Warn("Skipped job: failed pre-move validation for subjob",
@@ -33,18 +31,10 @@ func ValidateMoveable(m *schema.Moveable) bool {
 				"share", m.Share.GetName(),
 			)
 
-			hardLinkFailure = true
-
-			break
+			return err
 		}
 	}
 
-	if hardLinkFailure {
-		return false
-	}
-
-	symlinkFailure := false
-
 	for _, s := range m.Symlinks {
 		if err := validateMoveable(s); err != nil {
 			slog.Warn("Skipped job: failed pre-move validation for subjob",
@@ -55,17 +45,11 @@ func ValidateMoveable(m *schema.Moveable) bool {
 				"share", m.Share.GetName(),
 			)
 
-			symlinkFailure = true
-
-			break
+			return err
 		}
 	}
 
-	if symlinkFailure {
-		return false
-	}
-
-	return true
+	return nil
 }
 
 // validateMoveable is the principal function validate a single